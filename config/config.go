@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/codingconcepts/env"
 )
 
@@ -13,6 +15,18 @@ type Config struct {
 	DataPath string `env:"DATA_PATH" default:"/Users/rinse/work/games/coda-data"`
 	// StatePath is where the history of the game is stored
 	StatePath string `env:"STATE_PATH" default:"state"`
+	// LinkdeadGracePeriod is how long a character stays in the world after their connection drops
+	LinkdeadGracePeriod time.Duration `env:"LINKDEAD_GRACE_PERIOD" default:"5m"`
+	// MaxCharacters is how many characters each account can have
+	MaxCharacters int `env:"MAX_CHARACTERS" default:"3"`
+	// ShutdownTimeout is how long the server waits for the final save when it is stopped
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"10s"`
+	// DataWatch is how changes to the data folder are noticed, "notify" to be told by the operating system or "poll" to check regularly
+	DataWatch string `env:"DATA_WATCH" default:"notify"`
+	// DataPollInterval is how often the data folder is checked when polling
+	DataPollInterval time.Duration `env:"DATA_POLL_INTERVAL" default:"1m"`
+	// AdminUsers is a comma separated list of usernames that are always admins, they must already be registered
+	AdminUsers []string `env:"ADMIN_USERS"`
 }
 
 func Load() (*Config, error) {
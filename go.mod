@@ -6,12 +6,14 @@ require (
 	github.com/BurntSushi/toml v1.3.2
 	github.com/aybabtme/rgbterm v0.0.0-20170906152045-cc83f3b3ce59
 	github.com/codingconcepts/env v0.0.0-20200821220118-a8fbf8d84482
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/google/uuid v1.6.0
 	github.com/nicklanng/fsdiff v0.0.0-20180508113347-04d25b0226a2
 	github.com/pkg/errors v0.9.1
 	github.com/soupstoregames/go-core v0.0.0-20221010190337-a35636b94d5c
 	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/crypto v0.19.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
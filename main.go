@@ -1,8 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/soupstoregames/coda-mud/config"
@@ -21,18 +26,41 @@ func main() {
 		stateData    *state.FileSystem
 		sim          *simulation.Simulation
 		usersManager *services.UsersManager
+		auditFile    *os.File
 		err          error
 	)
 
-	logging.Info("Starting")
-
 	// load the configuration from environmental variables
 	if conf, err = config.Load(); err != nil {
 		logging.Fatal(err.Error())
 	}
 
+	// subcommands run instead of the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "role":
+			if err := setRole(conf, os.Args[2:]); err != nil {
+				logging.Fatal(err.Error())
+			}
+		case "validate":
+			if !validate(conf, os.Args[2:]) {
+				os.Exit(1)
+			}
+		case "export":
+			if err := export(conf, os.Args[2:]); err != nil {
+				logging.Fatal(err.Error())
+			}
+		default:
+			logging.Fatal(fmt.Sprintf("Unknown command '%s'", os.Args[1]))
+		}
+		return
+	}
+
+	logging.Info("Starting")
+
 	// create the simulation
 	sim = simulation.NewSimulation()
+	sim.SetLinkdeadGracePeriod(conf.LinkdeadGracePeriod)
 
 	// create the static data loader
 	staticData = static.NewDataWatcher(conf.DataPath, sim)
@@ -51,27 +79,152 @@ func main() {
 		logging.Fatal(err.Error())
 	}
 
-	// start watching for changes to the static data folder
-	staticData.Watch()
+	// start watching for changes to the static data folder, polling if the operating system can't tell us about them
+	switch conf.DataWatch {
+	case "notify":
+		if err := staticData.Watch(); err != nil {
+			logging.Warn(fmt.Sprintf("Can't watch the data folder, polling it instead: %s", err.Error()))
+			staticData.Poll(conf.DataPollInterval)
+		}
+	case "poll":
+		staticData.Poll(conf.DataPollInterval)
+	default:
+		logging.Fatal(fmt.Sprintf("Unknown data watch mode '%s'", conf.DataWatch))
+	}
+
+	// let builders save their changes to the static data folder
+	sim.SetDataWriter(static.NewDataWriter(staticData))
+
+	// staff can only act on users whose role is lower than theirs, unless they are admins
+	sim.SetModerators(usersManager)
+
+	// set the spawn room from the start world, new characters begin here and characters that can't be put back where they were saved go here
+	spawnWorldID, spawnRoomID := staticData.SpawnRoom()
+	if err := sim.SetSpawnRoom(spawnWorldID, spawnRoomID); err != nil {
+		logging.Fatal(fmt.Sprintf("Spawn room %d in world '%s': %s", spawnRoomID, spawnWorldID, err.Error()))
+	}
 
 	// load the saved state
 	loadState(stateData, usersManager, sim)
 
-	// set the @spawn room
-	sim.SetSpawnRoom("@spawn", 1)
+	// the admin list can only name accounts that are already registered
+	for _, username := range usersManager.SetAdmins(conf.AdminUsers) {
+		logging.Warn(fmt.Sprintf("ADMIN_USERS names '%s', who has not registered, so they were not made an admin", username))
+	}
 
 	// set up save timing for simulation state
-	startSaveSimulationTicker(usersManager, sim, stateData)
+	saveTicker := startSaveSimulationTicker(usersManager, sim, stateData)
 
 	// start the simulation
 	sim.Start()
 
+	// privileged commands are recorded alongside the saved state
+	if auditFile, err = os.OpenFile(filepath.Join(conf.StatePath, "audit.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+		logging.Fatal(err.Error())
+	}
+	defer auditFile.Close()
+
 	// start the telnet server
-	telnetServer := telnet.NewServer(conf, sim, usersManager)
-	if err = telnetServer.ListenAndServe(); err != nil {
-		log.Fatal(err.Error())
+	telnetServer := telnet.NewServer(conf, sim, usersManager, services.NewAuditLog(auditFile))
+	serveErrors := make(chan error, 1)
+	go func() {
+		serveErrors <- telnetServer.ListenAndServe()
+	}()
+
+	// run until we are told to stop, by a signal or an admin
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case sig := <-signals:
+		logging.Info(fmt.Sprintf("Received %s, shutting down", sig))
+	case <-sim.ShutdownRequested():
+		logging.Info("Shutdown requested in game")
+	case err := <-serveErrors:
+		logging.Error(fmt.Sprintf("Telnet server stopped: %s", err))
+	}
+
+	saveTicker.Stop()
+	shutdown(conf.ShutdownTimeout, telnetServer, usersManager, sim, stateData)
+}
+
+// shutdown stops new players connecting, sends everyone playing to sleep and saves the game one last time.
+// It gives up waiting after the timeout so that a stuck connection can't keep the server running.
+func shutdown(timeout time.Duration, telnetServer *telnet.Server, u *services.UsersManager, s *simulation.Simulation, p state.Persister) {
+	done := make(chan struct{})
+	go func() {
+		if err := telnetServer.Close(); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to stop telnet server: %s", err.Error()))
+		}
+		s.Shutdown()
+		save(u, s, p)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logging.Info("Shut down")
+	case <-time.After(timeout):
+		logging.Warn(fmt.Sprintf("Gave up waiting for shutdown after %v", timeout))
+	}
+}
+
+// export converts the data folder into another format, for example "coda-mud export json ./data-json".
+// A third argument exports a different data folder than the configured one.
+func export(conf *config.Config, args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("usage: export <toml|json|yaml> <export folder> [data folder]")
+	}
+
+	dataPath := conf.DataPath
+	if len(args) == 3 {
+		dataPath = args[2]
+	}
+
+	return static.Export(dataPath, args[1], args[0])
+}
+
+// setRole gives a user a role from the command line, for example "coda-mud role alice admin".
+// It should be run while the server is stopped, otherwise the server will save over it.
+func setRole(conf *config.Config, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: role <username> <%s|%s|%s|%s>", services.RolePlayer, services.RoleBuilder, services.RoleModerator, services.RoleAdmin)
+	}
+
+	role, err := services.ParseRole(args[1])
+	if err != nil {
+		return err
 	}
 
+	stateData, err := state.NewFileSystem(conf)
+	if err != nil {
+		return err
+	}
+
+	users, _, _, err := stateData.Load()
+	if err != nil {
+		return err
+	}
+
+	usersManager := services.NewUsersManager()
+	if err := usersManager.Load(users); err != nil {
+		return err
+	}
+
+	if err := usersManager.SetRole(args[0], role); err != nil {
+		return err
+	}
+
+	if err := usersManager.Save(stateData); err != nil {
+		return err
+	}
+
+	if err := stateData.Persist(); err != nil {
+		return err
+	}
+
+	logging.Info(fmt.Sprintf("%s is now %s", args[0], role))
+	return nil
 }
 
 func loadState(loader state.Loader, usersManager *services.UsersManager, sim simulation.StateController) {
@@ -85,24 +238,74 @@ func loadState(loader state.Loader, usersManager *services.UsersManager, sim sim
 	if users, characters, worlds, err = loader.Load(); err != nil {
 		logging.Fatal(err.Error())
 	}
-	if usersManager.Load(users); err != nil {
+	if err = usersManager.Load(users); err != nil {
 		logging.Fatal(err.Error())
 	}
-	if sim.Load(characters, worlds); err != nil {
+	if err = sim.Load(characters, worlds); err != nil {
 		logging.Fatal(err.Error())
 	}
 }
 
-func startSaveSimulationTicker(u *services.UsersManager, s *simulation.Simulation, p state.Persister) {
+// saveLock stops the final save at shutdown overlapping a regular save.
+var saveLock sync.Mutex
+
+func startSaveSimulationTicker(u *services.UsersManager, s *simulation.Simulation, p state.Persister) *time.Ticker {
 	t := time.NewTicker(time.Minute)
 	go func() {
 		for range t.C {
-			if err := u.Save(p); err != nil {
-				logging.Warn(fmt.Sprintf("Failed to save users: %s", err.Error()))
-			}
-			if err := s.Save(p); err != nil {
-				logging.Warn(fmt.Sprintf("Failed to save simulation state: %s", err.Error()))
-			}
+			save(u, s, p)
 		}
 	}()
+	return t
+}
+
+func save(u *services.UsersManager, s *simulation.Simulation, p state.Persister) {
+	saveLock.Lock()
+	defer saveLock.Unlock()
+
+	if err := u.Save(p); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save users: %s", err.Error()))
+	}
+	if err := s.Save(p); err != nil {
+		logging.Warn(fmt.Sprintf("Failed to save simulation state: %s", err.Error()))
+	}
+}
+
+// validate checks the data folder, by default the configured one, and prints the problems as JSON.
+// It returns false if there are any errors, warnings alone are not enough to fail.
+func validate(conf *config.Config, args []string) bool {
+	dataPath := conf.DataPath
+	if len(args) > 0 {
+		dataPath = args[0]
+	}
+
+	problems := static.Validate(dataPath)
+
+	report := struct {
+		DataPath string           `json:"data_path"`
+		Errors   int              `json:"errors"`
+		Warnings int              `json:"warnings"`
+		Problems []static.Problem `json:"problems"`
+	}{
+		DataPath: dataPath,
+		Problems: problems,
+	}
+	if report.Problems == nil {
+		report.Problems = []static.Problem{}
+	}
+	for _, problem := range problems {
+		if problem.Severity == static.SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logging.Fatal(err.Error())
+	}
+
+	return report.Errors == 0
 }
@@ -1,9 +1,13 @@
 package telnet
 
 import (
+	"errors"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/soupstoregames/coda-mud/services"
 	"github.com/soupstoregames/coda-mud/simulation"
 	"github.com/soupstoregames/coda-mud/simulation/model"
 )
@@ -17,48 +21,128 @@ import (
 //}
 
 // Command is a function alias for commands in the game state.
-type Command func(characterID model.CharacterID, sim *simulation.Simulation, args []string) error
+type Command func(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error
+
+// commandDefinition is an entry in the command table, along with the role needed to use it.
+type commandDefinition struct {
+	run  Command
+	role services.Role
+}
 
 // all the commands available to be used in the world state.
-var commands = map[string]Command{
-	"@spawn":    CmdAdminSpawn,
-	"look":      CmdLook,
-	"l":         CmdLook,
-	"say":       CmdSay,
-	"quit":      CmdQuit,
-	"north":     CmdNorth,
-	"n":         CmdNorth,
-	"northeast": CmdNorthEast,
-	"ne":        CmdNorthEast,
-	"east":      CmdEast,
-	"e":         CmdEast,
-	"southeast": CmdSouthEast,
-	"se":        CmdSouthEast,
-	"up":        CmdUp,
-	"u":         CmdUp,
-	"south":     CmdSouth,
-	"s":         CmdSouth,
-	"southwest": CmdSouthWest,
-	"sw":        CmdSouthWest,
-	"west":      CmdWest,
-	"w":         CmdWest,
-	"northwest": CmdNorthWest,
-	"nw":        CmdNorthWest,
-	"down":      CmdDown,
-	"d":         CmdDown,
-	"take":      CmdTake,
-	"get":       CmdTake,
-	"drop":      CmdDrop,
-	"equip":     CmdEquip,
-	"wear":      CmdEquip,
-	"unequip":   CmdUnequip,
-	"remove":    CmdUnequip,
-	"inventory": CmdInventory,
-	"i":         CmdInventory,
+var commands = map[string]commandDefinition{
+	"@spawn":    {CmdAdminSpawn, services.RoleBuilder},
+	"@goto":     {CmdAdminGoto, services.RoleBuilder},
+	"@inspect":  {CmdAdminInspect, services.RoleBuilder},
+	"@summon":   {CmdAdminSummon, services.RoleModerator},
+	"@teleport": {CmdAdminTeleport, services.RoleModerator},
+	"@kick":     {CmdAdminKick, services.RoleModerator},
+	"@set":      {CmdAdminSet, services.RoleModerator},
+	"@purge":    {CmdAdminPurge, services.RoleModerator},
+	"@dig":      {CmdBuildDig, services.RoleBuilder},
+	"@describe": {CmdBuildDescribe, services.RoleBuilder},
+	"@rename":   {CmdBuildRename, services.RoleBuilder},
+	"@region":   {CmdBuildRegion, services.RoleBuilder},
+	"@exit":     {CmdBuildExit, services.RoleBuilder},
+	"@itemdef":  {CmdBuildItemDefinition, services.RoleBuilder},
+	"@reload":   {CmdBuildReload, services.RoleBuilder},
+	"@shutdown": {CmdAdminShutdown, services.RoleAdmin},
+	"look":      {CmdLook, services.RolePlayer},
+	"l":         {CmdLook, services.RolePlayer},
+	"say":       {CmdSay, services.RolePlayer},
+	"quit":      {CmdQuit, services.RolePlayer},
+	"north":     {CmdNorth, services.RolePlayer},
+	"n":         {CmdNorth, services.RolePlayer},
+	"northeast": {CmdNorthEast, services.RolePlayer},
+	"ne":        {CmdNorthEast, services.RolePlayer},
+	"east":      {CmdEast, services.RolePlayer},
+	"e":         {CmdEast, services.RolePlayer},
+	"southeast": {CmdSouthEast, services.RolePlayer},
+	"se":        {CmdSouthEast, services.RolePlayer},
+	"up":        {CmdUp, services.RolePlayer},
+	"u":         {CmdUp, services.RolePlayer},
+	"south":     {CmdSouth, services.RolePlayer},
+	"s":         {CmdSouth, services.RolePlayer},
+	"southwest": {CmdSouthWest, services.RolePlayer},
+	"sw":        {CmdSouthWest, services.RolePlayer},
+	"west":      {CmdWest, services.RolePlayer},
+	"w":         {CmdWest, services.RolePlayer},
+	"northwest": {CmdNorthWest, services.RolePlayer},
+	"nw":        {CmdNorthWest, services.RolePlayer},
+	"down":      {CmdDown, services.RolePlayer},
+	"d":         {CmdDown, services.RolePlayer},
+	"take":      {CmdTake, services.RolePlayer},
+	"get":       {CmdTake, services.RolePlayer},
+	"drop":      {CmdDrop, services.RolePlayer},
+	"equip":     {CmdEquip, services.RolePlayer},
+	"wear":      {CmdEquip, services.RolePlayer},
+	"unequip":   {CmdUnequip, services.RolePlayer},
+	"remove":    {CmdUnequip, services.RolePlayer},
+	"inventory": {CmdInventory, services.RolePlayer},
+	"i":         {CmdInventory, services.RolePlayer},
+	"open":      {CmdOpen, services.RolePlayer},
+	"close":     {CmdClose, services.RolePlayer},
+	"lock":      {CmdLock, services.RolePlayer},
+	"unlock":    {CmdUnlock, services.RolePlayer},
+	"search":    {CmdSearch, services.RolePlayer},
+	"go":        {CmdGo, services.RolePlayer},
+	"list":      {CmdList, services.RolePlayer},
+	"buy":       {CmdBuy, services.RolePlayer},
+	"sell":      {CmdSell, services.RolePlayer},
+	"value":     {CmdValue, services.RolePlayer},
+	"craft":     {CmdCraft, services.RolePlayer},
+	"quests":    {CmdQuests, services.RolePlayer},
+	"quest":     {CmdQuest, services.RolePlayer},
+	"talk":      {CmdTalk, services.RolePlayer},
+	"follow":    {CmdFollow, services.RolePlayer},
+	"group":     {CmdGroup, services.RolePlayer},
+	"party":     {CmdParty, services.RolePlayer},
+	"p":         {CmdParty, services.RolePlayer},
+	"who":       {CmdWho, services.RolePlayer},
+	"tell":      {CmdTell, services.RolePlayer},
+	"reply":     {CmdReply, services.RolePlayer},
+	"r":         {CmdReply, services.RolePlayer},
+	"whisper":   {CmdWhisper, services.RolePlayer},
+	"shout":     {CmdShout, services.RolePlayer},
+	"emote":     {CmdEmote, services.RolePlayer},
+	"me":        {CmdEmote, services.RolePlayer},
+	"channel":   {CmdChannel, services.RolePlayer},
+}
+
+// directionAliases are the short forms of directions that players can type.
+var directionAliases = map[string]model.Direction{
+	"n":  model.DirectionNorth,
+	"ne": model.DirectionNorthEast,
+	"e":  model.DirectionEast,
+	"se": model.DirectionSouthEast,
+	"u":  model.DirectionUp,
+	"s":  model.DirectionSouth,
+	"sw": model.DirectionSouthWest,
+	"w":  model.DirectionWest,
+	"nw": model.DirectionNorthWest,
+	"d":  model.DirectionDown,
+}
+
+// parseDirection reads a direction from the command arguments, accepting both full names and short forms.
+func parseDirection(args []string) (model.Direction, error) {
+	if len(args) == 0 {
+		return model.DirectionNorth, errors.New("which direction?")
+	}
+
+	text := strings.ToLower(args[len(args)-1])
+	if d, ok := directionAliases[text]; ok {
+		return d, nil
+	}
+
+	d, err := model.StringToDirection(text)
+	if err != nil {
+		return model.DirectionNorth, errors.New("which direction?")
+	}
+	return d, nil
 }
 
 // CmdAdminSpawn allows admins to @spawn in items into the world.
-func CmdAdminSpawn(characterID model.CharacterID, sim *simulation.Simulation, args []string) error {
+func CmdAdminSpawn(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
 	switch args[0] {
 	case "item":
 		sItemDefinitionID := args[1]
@@ -71,6 +155,213 @@ func CmdAdminSpawn(characterID model.CharacterID, sim *simulation.Simulation, ar
 	return nil
 }
 
+// CmdAdminGoto moves the admin to a character with "@goto <name>", or to a room with "@goto [world] <room>".
+func CmdAdminGoto(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	switch len(args) {
+	case 1:
+		roomID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return sim.QueueCommand(characterID, session, model.CommandAdminGoto{Target: args[0]})
+		}
+		return sim.QueueCommand(characterID, session, model.CommandAdminGoto{RoomID: model.RoomID(roomID)})
+	case 2:
+		roomID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New("usage: @goto <name> or @goto [world] <room>")
+		}
+		return sim.QueueCommand(characterID, session, model.CommandAdminGoto{WorldID: model.WorldID(args[0]), RoomID: model.RoomID(roomID)})
+	}
+	return errors.New("usage: @goto <name> or @goto [world] <room>")
+}
+
+// CmdAdminSummon brings a character to the admin's room.
+func CmdAdminSummon(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: @summon <name>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandAdminSummon{Target: args[0]})
+}
+
+// CmdAdminTeleport moves a character to a room with "@teleport <name> <world> <room>".
+func CmdAdminTeleport(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) != 3 {
+		return errors.New("usage: @teleport <name> <world> <room>")
+	}
+	roomID, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return errors.New("usage: @teleport <name> <world> <room>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandAdminTeleport{
+		Target:  args[0],
+		WorldID: model.WorldID(args[1]),
+		RoomID:  model.RoomID(roomID),
+	})
+}
+
+// CmdAdminKick disconnects a character.
+func CmdAdminKick(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: @kick <name>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandAdminKick{Target: args[0]})
+}
+
+// CmdAdminInspect shows everything about the room, a character or an item. On its own it inspects the room.
+func CmdAdminInspect(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	return sim.QueueCommand(characterID, session, model.CommandAdminInspect{Target: strings.Join(args, " ")})
+}
+
+// CmdAdminSet changes a character's field with "@set <name> <field> <value>".
+func CmdAdminSet(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) < 3 {
+		return errors.New("usage: @set <name> <name|description|money|strength|dexterity|constitution|intelligence> <value>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandAdminSet{
+		Target: args[0],
+		Field:  args[1],
+		Value:  strings.Join(args[2:], " "),
+	})
+}
+
+// CmdAdminPurge destroys every item on the floor of the admin's room.
+func CmdAdminPurge(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	return sim.QueueCommand(characterID, session, model.CommandAdminPurge{})
+}
+
+// CmdBuildDig makes a new room with "@dig <direction> <name>", linked both ways to the builder's room.
+func CmdBuildDig(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: @dig <direction> <name>")
+	}
+	direction, err := parseDirection(args[:1])
+	if err != nil {
+		return err
+	}
+	return sim.QueueCommand(characterID, session, model.CommandBuildDig{
+		Direction: direction,
+		Name:      strings.Join(args[1:], " "),
+	})
+}
+
+// CmdBuildDescribe sets the description of the builder's room.
+func CmdBuildDescribe(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: @describe <description>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandBuildDescribe{Description: strings.Join(args, " ")})
+}
+
+// CmdBuildRename sets the name of the builder's room.
+func CmdBuildRename(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: @rename <name>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandBuildRename{Name: strings.Join(args, " ")})
+}
+
+// CmdBuildRegion sets the region of the builder's room.
+func CmdBuildRegion(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: @region <region>")
+	}
+	return sim.QueueCommand(characterID, session, model.CommandBuildRegion{Region: strings.Join(args, " ")})
+}
+
+// CmdBuildExit changes the exits of the builder's room with "@exit link <direction> [world] <room>" and "@exit unlink <direction>".
+func CmdBuildExit(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	usage := errors.New("usage: @exit link <direction> [world] <room> or @exit unlink <direction>")
+	if len(args) < 2 {
+		return usage
+	}
+
+	direction, err := parseDirection(args[1:2])
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "link":
+		var worldID model.WorldID
+		switch len(args) {
+		case 3:
+		case 4:
+			worldID = model.WorldID(args[2])
+		default:
+			return usage
+		}
+		roomID, err := strconv.ParseInt(args[len(args)-1], 10, 64)
+		if err != nil {
+			return usage
+		}
+		return sim.QueueCommand(characterID, session, model.CommandBuildExitLink{
+			Direction: direction,
+			WorldID:   worldID,
+			RoomID:    model.RoomID(roomID),
+		})
+	case "unlink":
+		return sim.QueueCommand(characterID, session, model.CommandBuildExitUnlink{Direction: direction})
+	}
+	return usage
+}
+
+// CmdBuildItemDefinition makes item definitions with "@itemdef create <name>" and changes them with "@itemdef edit <id> <field> <value>".
+func CmdBuildItemDefinition(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	usage := errors.New("usage: @itemdef create <name> or @itemdef edit <id> <field> <value>")
+	if len(args) < 2 {
+		return usage
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "create":
+		return sim.QueueCommand(characterID, session, model.CommandBuildItemDefinitionCreate{Name: strings.Join(args[1:], " ")})
+	case "edit":
+		if len(args) < 4 {
+			return usage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usage
+		}
+		return sim.QueueCommand(characterID, session, model.CommandBuildItemDefinitionEdit{
+			ID:    model.ItemDefinitionID(id),
+			Field: args[2],
+			Value: strings.Join(args[3:], " "),
+		})
+	}
+	return usage
+}
+
+// CmdBuildReload loads changes to the data folder straight away.
+func CmdBuildReload(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	return sim.QueueCommand(characterID, session, model.CommandBuildReload{})
+}
+
+// CmdAdminShutdown stops the server with "@shutdown [delay]", straight away if there is no delay.
+// The delay is in seconds or a duration such as "5m". "@shutdown cancel" stops a scheduled shutdown.
+func CmdAdminShutdown(characterID model.CharacterID, session string, sim *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return sim.QueueCommand(characterID, session, model.CommandAdminShutdown{})
+	}
+
+	if strings.EqualFold(args[0], "cancel") {
+		return sim.QueueCommand(characterID, session, model.CommandAdminShutdownCancel{})
+	}
+
+	delay, err := time.ParseDuration(args[0])
+	if err != nil {
+		seconds, err := strconv.Atoi(args[0])
+		if err != nil {
+			return errors.New("usage: @shutdown [delay|cancel]")
+		}
+		delay = time.Duration(seconds) * time.Second
+	}
+	if delay < 0 {
+		return errors.New("usage: @shutdown [delay|cancel]")
+	}
+
+	return sim.QueueCommand(characterID, session, model.CommandAdminShutdown{Delay: delay})
+}
+
 //// CmdConnect is the command used to login to the MUD.
 //func CmdConnect(conn *connection, args []string) error {
 //	if len(args) != 2 {
@@ -138,140 +429,511 @@ func CmdAdminSpawn(characterID model.CharacterID, sim *simulation.Simulation, ar
 // all of the commands available to be used in the world state.
 
 // CmdLook will trigger another description of the room the character is currently in.
-func CmdLook(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.Look(characterID)
+// Given a target, such as "look fountain" or "look at sword", it describes that instead.
+func CmdLook(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) > 0 && strings.ToLower(args[0]) == "at" {
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		return cc.Look(characterID)
+	}
+
+	return cc.LookAt(characterID, strings.Join(args, " "))
 }
 
 // CmdInventory lists the character's current equipment and items in containers.
-func CmdInventory(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
+func CmdInventory(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
 	return cc.Inventory(characterID)
 }
 
 // CmdQuit sends the character to sleep and disconnects the user.
-func CmdQuit(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.SleepCharacter(characterID)
+func CmdQuit(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.SleepCharacter(characterID, session)
 }
 
 // CmdSay makes the character speak to all other characters in the same room.
-func CmdSay(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandSay{
+func CmdSay(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandSay{
 		Content: strings.Join(args, " "),
 	})
 }
 
 // CmdTake has the character pick up an item from the room and put it into their inventory.
-func CmdTake(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
+// Part of a stack can be taken by giving a quantity, such as "take 10 coins".
+func CmdTake(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	quantity, args := parseQuantity(args)
 	item, err := cc.FindItemInRoom(characterID, strings.Join(args, " "))
 	if err != nil {
 		return err
 	}
-	return cc.QueueCommand(characterID, model.CommandTake{
-		Item: item,
+	return cc.QueueCommand(characterID, session, model.CommandTake{
+		Item:     item,
+		Quantity: quantity,
 	})
 }
 
 // CmdDrop allows the character to drop an item from their inventory on to the floor.
-func CmdDrop(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
+// Part of a stack can be dropped by giving a quantity, such as "drop 5 arrows".
+func CmdDrop(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	quantity, args := parseQuantity(args)
 	item, err := cc.FindItemInInventory(characterID, strings.Join(args, " "))
 	if err != nil {
 		return err
 	}
 
-	return cc.QueueCommand(characterID, model.CommandDrop{
-		Item: item,
+	return cc.QueueCommand(characterID, session, model.CommandDrop{
+		Item:     item,
+		Quantity: quantity,
 	})
 }
 
+// parseQuantity reads a leading number off the command arguments.
+// It returns 0 if there isn't one, which means all of them.
+func parseQuantity(args []string) (int, []string) {
+	if len(args) < 2 {
+		return 0, args
+	}
+
+	quantity, err := strconv.Atoi(args[0])
+	if err != nil || quantity <= 0 {
+		return 0, args
+	}
+
+	return quantity, args[1:]
+}
+
 // CmdEquip allows the character to equip an item to his rig.
-func CmdEquip(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
+func CmdEquip(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
 	item, err := cc.FindItemInInventory(characterID, strings.Join(args, " "))
 	if err != nil {
 		return err
 	}
 
-	return cc.QueueCommand(characterID, model.CommandEquip{
+	return cc.QueueCommand(characterID, session, model.CommandEquip{
 		Item: item,
 	})
 }
 
 // CmdUnequip takes an item off the character's rig and stores it.
-func CmdUnequip(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
+func CmdUnequip(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
 	item, err := cc.FindItemInRig(characterID, strings.Join(args, " "))
 	if err != nil {
 		return err
 	}
 
-	return cc.QueueCommand(characterID, model.CommandUnequip{
+	return cc.QueueCommand(characterID, session, model.CommandUnequip{
 		Item: item,
 	})
 }
 
+// CmdOpen opens the door in the given direction.
+func CmdOpen(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	direction, err := parseDirection(args)
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandOpen{
+		Direction: direction,
+	})
+}
+
+// CmdClose closes the door in the given direction.
+func CmdClose(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	direction, err := parseDirection(args)
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandClose{
+		Direction: direction,
+	})
+}
+
+// CmdLock locks the door in the given direction, if the character has the key.
+func CmdLock(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	direction, err := parseDirection(args)
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandLock{
+		Direction: direction,
+	})
+}
+
+// CmdUnlock unlocks the door in the given direction, if the character has the key.
+func CmdUnlock(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	direction, err := parseDirection(args)
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandUnlock{
+		Direction: direction,
+	})
+}
+
+// CmdSearch looks around the room for hidden exits.
+func CmdSearch(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandSearch{})
+}
+
+// CmdGo moves the character through an exit, given either as a direction or as a named exit such as "go portal".
+func CmdGo(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 1 {
+		if direction, err := parseDirection(args); err == nil {
+			return cc.QueueCommand(characterID, session, model.CommandMove{
+				Direction: direction,
+			})
+		}
+	}
+
+	return moveThroughNamedExit(characterID, session, cc, strings.Join(args, " "))
+}
+
+// moveThroughNamedExit finds a named exit matching the text and moves the character through it.
+func moveThroughNamedExit(characterID model.CharacterID, session string, cc *simulation.Simulation, text string) error {
+	keyword, err := cc.FindNamedExit(characterID, text)
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandMove{
+		Keyword: keyword,
+	})
+}
+
+// CmdList shows what the shop in the room has for sale.
+func CmdList(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.ListShop(characterID)
+}
+
+// CmdBuy buys an item from the shop in the room, such as "buy sword" or "buy 20 arrows".
+func CmdBuy(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	quantity, args := parseQuantity(args)
+	if len(args) == 0 {
+		return errors.New("buy what?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandBuy{
+		Alias:    strings.Join(args, " "),
+		Quantity: quantity,
+	})
+}
+
+// CmdSell sells an item from the character's inventory to the shop in the room.
+func CmdSell(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	quantity, args := parseQuantity(args)
+	item, err := cc.FindItemInInventory(characterID, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandSell{
+		Item:     item,
+		Quantity: quantity,
+	})
+}
+
+// CmdValue asks the shop in the room what it would pay for an item in the character's inventory.
+func CmdValue(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	item, err := cc.FindItemInInventory(characterID, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	return cc.ValueItem(characterID, item)
+}
+
+// CmdCraft makes an item out of other items by following a recipe.
+func CmdCraft(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	recipe, err := cc.FindRecipe(characterID, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandCraft{
+		Recipe: recipe,
+	})
+}
+
+// CmdQuests lists the character's quests.
+func CmdQuests(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QuestLog(characterID)
+}
+
+// CmdQuest describes one of the character's quests, or lists them all if no name is given.
+func CmdQuest(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return cc.QuestLog(characterID)
+	}
+
+	return cc.QuestDetails(characterID, strings.Join(args, " "))
+}
+
+// CmdTalk talks to someone in the room, such as "talk guard" or "talk to guard".
+func CmdTalk(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) > 1 && strings.ToLower(args[0]) == "to" {
+		args = args[1:]
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandTalk{
+		Target: strings.ToLower(strings.Join(args, " ")),
+	})
+}
+
+// CmdFollow follows a character in the room. "follow me" or "follow" on its own stops following.
+func CmdFollow(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandFollow{
+		Target: strings.ToLower(strings.Join(args, " ")),
+	})
+}
+
+// CmdGroup manages the character's group: "group invite <name>", "group accept [name]", "group leave" and "group kick <name>".
+// On its own it lists the group's members.
+func CmdGroup(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return cc.GroupList(characterID)
+	}
+
+	name := strings.Join(args[1:], " ")
+
+	switch strings.ToLower(args[0]) {
+	case "invite":
+		if name == "" {
+			return errors.New("invite who?")
+		}
+		return cc.QueueCommand(characterID, session, model.CommandGroupInvite{Target: name})
+	case "accept", "join":
+		return cc.QueueCommand(characterID, session, model.CommandGroupAccept{Inviter: name})
+	case "leave":
+		return cc.QueueCommand(characterID, session, model.CommandGroupLeave{})
+	case "kick":
+		if name == "" {
+			return errors.New("kick who?")
+		}
+		return cc.QueueCommand(characterID, session, model.CommandGroupKick{Target: name})
+	default:
+		return errors.New("group invite, accept, leave or kick?")
+	}
+}
+
+// CmdParty talks to everyone in the character's group, wherever they are.
+func CmdParty(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("say what?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandPartyChat{
+		Content: strings.Join(args, " "),
+	})
+}
+
+// CmdWho lists everyone who is playing.
+func CmdWho(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.Who(characterID)
+}
+
+// CmdTell sends a message to another player wherever they are, such as "tell bob hello".
+func CmdTell(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) < 2 {
+		return errors.New("tell who what?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandTell{
+		Target:  args[0],
+		Content: strings.Join(args[1:], " "),
+	})
+}
+
+// CmdReply sends a tell back to whoever last sent the character one.
+func CmdReply(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("reply with what?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandReply{
+		Content: strings.Join(args, " "),
+	})
+}
+
+// CmdWhisper says something to a character in the room that nobody else hears.
+func CmdWhisper(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) > 0 && strings.ToLower(args[0]) == "to" {
+		args = args[1:]
+	}
+	if len(args) < 2 {
+		return errors.New("whisper what to who?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandWhisper{
+		Target:  args[0],
+		Content: strings.Join(args[1:], " "),
+	})
+}
+
+// CmdShout says something that can be heard throughout the region.
+func CmdShout(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("shout what?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandShout{
+		Content: strings.Join(args, " "),
+	})
+}
+
+// CmdEmote describes the character doing something, such as "emote waves" which shows as "Bob waves".
+func CmdEmote(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return errors.New("emote what?")
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandEmote{
+		Content: strings.Join(args, " "),
+	})
+}
+
+// CmdChannel manages chat channels: "channel join <name>", "channel leave <name>" and "channel <name> <message>".
+// On its own it lists the channels the character has joined.
+func CmdChannel(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	if len(args) == 0 {
+		return cc.ChannelList(characterID)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "join":
+		name, err := parseChannelName(args[1:])
+		if err != nil {
+			return err
+		}
+		return cc.QueueCommand(characterID, session, model.CommandChannelJoin{Channel: name})
+	case "leave":
+		name, err := parseChannelName(args[1:])
+		if err != nil {
+			return err
+		}
+		return cc.QueueCommand(characterID, session, model.CommandChannelLeave{Channel: name})
+	default:
+		if len(args) < 2 {
+			return errors.New("say what?")
+		}
+		return sayOnChannel(characterID, session, cc, strings.ToLower(args[0]), args[1:])
+	}
+}
+
+// sayOnChannel sends a message to everyone on a chat channel.
+func sayOnChannel(characterID model.CharacterID, session string, cc *simulation.Simulation, channel string, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandChannelSay{
+		Channel: channel,
+		Content: strings.Join(args, " "),
+	})
+}
+
+// performSocial performs the social with the name, aimed at whoever is named in the arguments.
+func performSocial(characterID model.CharacterID, session string, cc *simulation.Simulation, name string, args []string) error {
+	social, err := cc.FindSocial(characterID, name)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 && (strings.ToLower(args[0]) == "at" || strings.ToLower(args[0]) == "to") {
+		args = args[1:]
+	}
+
+	return cc.QueueCommand(characterID, session, model.CommandSocial{
+		Social: social,
+		Target: strings.ToLower(strings.Join(args, " ")),
+	})
+}
+
+// parseChannelName reads a channel name from the command arguments.
+// Channel names are a single lower case word so that they can be typed as commands.
+func parseChannelName(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("which channel?")
+	}
+
+	name := strings.ToLower(args[0])
+	if len(name) > 20 {
+		return "", errors.New("channel names can be at most 20 letters long")
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "", errors.New("channel names can only contain letters and numbers")
+		}
+	}
+	return name, nil
+}
+
 // CmdNorth attempts to move the character through the north exit.
-func CmdNorth(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdNorth(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionNorth,
 	})
 }
 
 // CmdNorthEast attempts to move the character through the north east exit.
-func CmdNorthEast(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdNorthEast(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionNorthEast,
 	})
 }
 
 // CmdEast attempts to move the character through the east exit.
-func CmdEast(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdEast(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionEast,
 	})
 }
 
 // CmdSouthEast attempts to move the character through the south east exit.
-func CmdSouthEast(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdSouthEast(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionSouthEast,
 	})
 }
 
 // CmdUp attempts to move the character through the up exit.
-func CmdUp(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdUp(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionUp,
 	})
 }
 
 // CmdSouth attempts to move the character through the south exit.
-func CmdSouth(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdSouth(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionSouth,
 	})
 }
 
 // CmdSouthWest attempts to move the character through the south west exit.
-func CmdSouthWest(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdSouthWest(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionSouthWest,
 	})
 }
 
 // CmdWest attempts to move the character through the west exit.
-func CmdWest(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdWest(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionWest,
 	})
 }
 
 // CmdNorthWest attempts to move the character through the north west exit.
-func CmdNorthWest(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdNorthWest(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionNorthWest,
 	})
 }
 
 // CmdDown attempts to move the character through the down exit.
-func CmdDown(characterID model.CharacterID, cc *simulation.Simulation, args []string) error {
-	return cc.QueueCommand(characterID, model.CommandMove{
+func CmdDown(characterID model.CharacterID, session string, cc *simulation.Simulation, args []string) error {
+	return cc.QueueCommand(characterID, session, model.CommandMove{
 		Direction: model.DirectionDown,
 	})
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/soupstoregames/go-core/logging"
 	"net"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,11 +22,12 @@ type connection struct {
 	config       *config.Config
 	sim          *simulation.Simulation
 	usersManager *services.UsersManager
+	auditLog     *services.AuditLog
 
 	conn          net.Conn
 	ctx           context.Context
 	state         data.Stack[state]
-	closed        bool
+	closeOnce     sync.Once
 	stopHeartbeat chan struct{}
 
 	willNAWS bool
@@ -33,12 +35,13 @@ type connection struct {
 	height   int
 }
 
-func newTelnetConnection(c net.Conn, conf *config.Config, sim *simulation.Simulation, usersManager *services.UsersManager) *connection {
+func newTelnetConnection(c net.Conn, conf *config.Config, sim *simulation.Simulation, usersManager *services.UsersManager, auditLog *services.AuditLog) *connection {
 	conn := &connection{
 		config:       conf,
 		conn:         c,
 		sim:          sim,
 		usersManager: usersManager,
+		auditLog:     auditLog,
 		ctx:          context.Background(),
 	}
 
@@ -58,17 +61,14 @@ func newTelnetConnection(c net.Conn, conf *config.Config, sim *simulation.Simula
 	return conn
 }
 
+// close can be called by both the connection and the renderer, but only closes the connection once.
 func (c *connection) close() {
-	if c.closed {
-		return
-	}
+	c.closeOnce.Do(c.closeConnection)
+}
 
+func (c *connection) closeConnection() {
 	logging.Debug("Closing telnet connection")
 
-	// set flag to avoid close being called twice
-	// without this you can try to close a closed channel
-	c.closed = true
-
 	// stop the heartbeat
 	c.stopHeartbeat <- struct{}{}
 
@@ -11,6 +11,7 @@ type key int
 const (
 	connectionIDKey key = iota
 	characterIDKey
+	usernameKey
 )
 
 // WithCharacterID returns the given context with the character ID in it.
@@ -25,10 +26,20 @@ func CharacterIDFromContext(c context.Context) model.CharacterID {
 
 // WithConnectionID returns the given context with the connection ID in it.
 func WithConnectionID(parent context.Context, connectionID string) context.Context {
-	return context.WithValue(parent, characterIDKey, connectionID)
+	return context.WithValue(parent, connectionIDKey, connectionID)
 }
 
 // ConnectionIDFromContext extracts the connection ID embedded in the context.
 func ConnectionIDFromContext(c context.Context) string {
 	return c.Value(connectionIDKey).(string)
 }
+
+// WithUsername returns the given context with the logged in user's name in it.
+func WithUsername(parent context.Context, username string) context.Context {
+	return context.WithValue(parent, usernameKey, username)
+}
+
+// UsernameFromContext extracts the username embedded in the context.
+func UsernameFromContext(c context.Context) string {
+	return c.Value(usernameKey).(string)
+}
@@ -3,6 +3,7 @@ package telnet
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aybabtme/rgbterm"
@@ -24,6 +25,22 @@ func renderEvents(c *connection, events <-chan interface{}) error {
 		case model.EvtCharacterFallsAsleep:
 			renderCharacterFallsAsleep(c, v)
 
+		case model.EvtCharacterLinkdead:
+			renderCharacterLinkdead(c, v)
+
+		case model.EvtCharacterReconnects:
+			renderCharacterReconnects(c, v)
+
+		case model.EvtServerShuttingDown:
+			renderServerShuttingDown(c)
+			c.close()
+			return nil
+
+		case model.EvtSessionTakenOver:
+			renderSessionTakenOver(c)
+			c.close()
+			return nil
+
 		case model.EvtNarration:
 			renderNarration(c, v)
 
@@ -39,15 +56,204 @@ func renderEvents(c *connection, events <-chan interface{}) error {
 		case model.EvtNoExitInThatDirection:
 			renderNoExitInThatDirection(c)
 
+		case model.EvtExitRevealed:
+			renderExitRevealed(c, v)
+
+		case model.EvtYouFindNothing:
+			renderYouFindNothing(c)
+
+		case model.EvtExitRefuses:
+			renderExitRefuses(c, v)
+
+		case model.EvtCharacterOpensDoor:
+			renderCharacterOpensDoor(c, v)
+
+		case model.EvtCharacterClosesDoor:
+			renderCharacterClosesDoor(c, v)
+
+		case model.EvtCharacterLocksDoor:
+			renderCharacterLocksDoor(c, v)
+
+		case model.EvtCharacterUnlocksDoor:
+			renderCharacterUnlocksDoor(c, v)
+
+		case model.EvtDoorChanges:
+			renderDoorChanges(c, v)
+
+		case model.EvtDoorIs:
+			renderDoorIs(c, v)
+
+		case model.EvtDoorBlocksExit:
+			renderDoorBlocksExit(c, v)
+
+		case model.EvtNoDoorInThatDirection:
+			renderNoDoorInThatDirection(c)
+
+		case model.EvtDoorHasNoLock:
+			renderDoorHasNoLock(c)
+
+		case model.EvtYouDoNotHaveTheKey:
+			renderYouDoNotHaveTheKey(c)
+
 		case model.EvtCharacterTakesItem:
 			renderCharacterTakesItem(c, v)
 
 		case model.EvtCharacterDropsItem:
 			renderCharacterDropsItem(c, v)
 
+		case model.EvtExtraDescription:
+			renderExtraDescription(c, v)
+
+		case model.EvtItemDescription:
+			renderItemDescription(c, v)
+
+		case model.EvtCharacterDescription:
+			renderCharacterDescription(c, v)
+
+		case model.EvtNothingToLookAt:
+			renderNothingToLookAt(c, v)
+
+		case model.EvtShopList:
+			renderShopList(c, v)
+
+		case model.EvtItemValue:
+			renderItemValue(c, v)
+
+		case model.EvtCharacterBuysItem:
+			renderCharacterBuysItem(c, v)
+
+		case model.EvtCharacterSellsItem:
+			renderCharacterSellsItem(c, v)
+
+		case model.EvtShopDoesNotSell:
+			renderShopDoesNotSell(c, v)
+
+		case model.EvtShopWillNotBuy:
+			renderShopWillNotBuy(c, v)
+
+		case model.EvtCannotAfford:
+			renderCannotAfford(c, v)
+
+		case model.EvtNoShopHere:
+			renderNoShopHere(c)
+
+		case model.EvtCharacterCrafts:
+			renderCharacterCrafts(c, v)
+
+		case model.EvtCraftingNeedsRoom:
+			renderCraftingNeedsRoom(c, v)
+
+		case model.EvtCraftingNeedsTool:
+			renderCraftingNeedsTool(c, v)
+
+		case model.EvtCraftingNeedsInput:
+			renderCraftingNeedsInput(c, v)
+
+		case model.EvtQuestStarted:
+			renderQuestStarted(c, v)
+
+		case model.EvtQuestAdvanced:
+			renderQuestAdvanced(c, v)
+
+		case model.EvtQuestCompleted:
+			renderQuestCompleted(c, v)
+
+		case model.EvtQuestLog:
+			renderQuestLog(c, v)
+
+		case model.EvtQuestDetails:
+			renderQuestDetails(c, v)
+
+		case model.EvtNobodyToTalkTo:
+			renderNobodyToTalkTo(c, v)
+
+		case model.EvtTell:
+			renderTell(c, v)
+
+		case model.EvtWhisper:
+			renderWhisper(c, v)
+
+		case model.EvtShout:
+			renderShout(c, v)
+
+		case model.EvtEmote:
+			renderEmote(c, v)
+
+		case model.EvtSocial:
+			renderSocial(c, v)
+
+		case model.EvtNobodyToReplyTo:
+			renderNobodyToReplyTo(c)
+
+		case model.EvtChannelMessage:
+			renderChannelMessage(c, v)
+
+		case model.EvtChannelJoined:
+			renderChannelJoined(c, v)
+
+		case model.EvtChannelLeft:
+			renderChannelLeft(c, v)
+
+		case model.EvtNotInChannel:
+			renderNotInChannel(c, v)
+
+		case model.EvtAlreadyInChannel:
+			renderAlreadyInChannel(c, v)
+
+		case model.EvtChannelList:
+			renderChannelList(c, v)
+
+		case model.EvtCharacterFollows:
+			renderCharacterFollows(c, v)
+
+		case model.EvtCharacterStopsFollowing:
+			renderCharacterStopsFollowing(c, v)
+
+		case model.EvtYouFollow:
+			renderYouFollow(c, v)
+
+		case model.EvtNotFollowing:
+			renderNotFollowing(c)
+
+		case model.EvtCharacterNotFound:
+			renderCharacterNotFound(c, v)
+
+		case model.EvtGroupInvite:
+			renderGroupInvite(c, v)
+
+		case model.EvtGroupJoined:
+			renderGroupJoined(c, v)
+
+		case model.EvtGroupLeft:
+			renderGroupLeft(c, v)
+
+		case model.EvtGroupList:
+			renderGroupList(c, v)
+
+		case model.EvtAlreadyInGroup:
+			renderAlreadyInGroup(c, v)
+
+		case model.EvtNotInGroup:
+			renderNotInGroup(c)
+
+		case model.EvtNotGroupLeader:
+			renderNotGroupLeader(c)
+
+		case model.EvtNoGroupInvite:
+			renderNoGroupInvite(c)
+
+		case model.EvtPartyChat:
+			renderPartyChat(c, v)
+
+		case model.EvtWho:
+			renderWho(c, v)
+
 		case model.EvtInventoryDescription:
 			renderInventoryDescription(c, v)
 
+		case model.EvtItemNotEquipable:
+			renderItemNotEquipable(c, v.Item)
+
 		case model.EvtYouAreNotWearing:
 			renderYouAreNotWearing(c, v.Alias)
 
@@ -57,6 +263,83 @@ func renderEvents(c *connection, events <-chan interface{}) error {
 		case model.EvtAdminSpawnsItem:
 			renderAdminSpawnsItem(c, v)
 
+		case model.EvtAdminTeleports:
+			renderAdminTeleports(c, v)
+
+		case model.EvtAdminRoomNotFound:
+			renderAdminRoomNotFound(c, v)
+
+		case model.EvtAdminKicks:
+			if v.Character.ID == characterID {
+				renderAdminKicks(c, v)
+				c.close()
+				return nil
+			}
+			renderAdminKicks(c, v)
+
+		case model.EvtAdminCannotKick:
+			renderAdminCannotKick(c, v)
+
+		case model.EvtAdminTargetNotFound:
+			renderAdminTargetNotFound(c, v)
+
+		case model.EvtAdminInspectCharacter:
+			renderAdminInspectCharacter(c, v)
+
+		case model.EvtAdminInspectRoom:
+			renderAdminInspectRoom(c, v)
+
+		case model.EvtAdminInspectItem:
+			renderAdminInspectItem(c, v)
+
+		case model.EvtAdminSetsField:
+			renderAdminSetsField(c, v)
+
+		case model.EvtAdminSetFailed:
+			renderAdminSetFailed(c, v)
+
+		case model.EvtAdminPurgesRoom:
+			renderAdminPurgesRoom(c, v)
+
+		case model.EvtShutdownScheduled:
+			renderShutdownScheduled(c, v)
+
+		case model.EvtShutdownCountdown:
+			renderShutdownCountdown(c, v)
+
+		case model.EvtShutdownCancelled:
+			renderShutdownCancelled(c, v)
+
+		case model.EvtNoShutdownScheduled:
+			renderNoShutdownScheduled(c)
+
+		case model.EvtBuildFailed:
+			renderBuildFailed(c, v)
+
+		case model.EvtBuilderDigs:
+			renderBuilderDigs(c, v)
+
+		case model.EvtRoomEdited:
+			renderRoomEdited(c, v)
+
+		case model.EvtExitLinked:
+			renderExitLinked(c, v)
+
+		case model.EvtExitUnlinked:
+			renderExitUnlinked(c, v)
+
+		case model.EvtItemDefinitionCreated:
+			renderItemDefinitionCreated(c, v)
+
+		case model.EvtItemDefinitionEdited:
+			renderItemDefinitionEdited(c, v)
+
+		case model.EvtDataReloaded:
+			renderDataReloaded(c)
+
+		case model.EvtDataReloadFailed:
+			renderDataReloadFailed(c, v)
+
 		case model.EvtCharacterEquipsItem:
 			renderCharacterEquipsItem(c, v)
 
@@ -78,37 +361,16 @@ func renderEvents(c *connection, events <-chan interface{}) error {
 		c.state.Peek().writePrompt()
 	}
 
+	// the simulation closes the events when the character stops being played by this connection,
+	// so that a connection that was taken over can't go on playing the character
+	c.close()
 	return nil
 }
 
 func renderRoomDescription(c *connection, characterID model.CharacterID, room *model.Room) {
 	c.writeln(styleLocation(room.Name, room.Region))
 
-	parser := Parser{}
-	roomDescription, err := parser.Parse(room.Description)
-	if err != nil {
-		logging.Error("Failed to parse room description")
-		c.writeln(styleDescription(room.Description))
-	} else {
-		var buf bytes.Buffer
-		for i := range roomDescription.Sections {
-			switch roomDescription.Sections[i].Type {
-			case SectionTypeCommand:
-				buf.Write(styleCommand(roomDescription.Sections[i].Text))
-			case SectionTypeSpeech:
-				buf.Write(styleSpeech(roomDescription.Sections[i].Text))
-			case SectionTypeHint:
-				buf.Write(styleHint(roomDescription.Sections[i].Text))
-			case SectionTypeDefault:
-				buf.Write(styleDescription(roomDescription.Sections[i].Text))
-			}
-		}
-		if c.willNAWS {
-			c.write([]byte(wrap(c.width, buf.String())))
-		} else {
-			c.write(buf.Bytes())
-		}
-	}
+	renderMarkup(c, room.Description)
 
 	if !room.Alone {
 		var awakeCharacters []string
@@ -147,12 +409,14 @@ func renderRoomDescription(c *connection, characterID model.CharacterID, room *m
 
 	// print items
 	var itemNames []string
+	stacked := false
 	for _, item := range room.Container.Items() {
-		itemNames = append(itemNames, item.Definition.Name)
+		itemNames = append(itemNames, item.DisplayName())
+		stacked = stacked || item.Quantity > 1
 	}
 	if len(itemNames) > 0 {
 		names, plural := renderList(itemNames)
-		if plural {
+		if plural || stacked {
 			c.writelnString(fmt.Sprintf("There are %s on the floor.", names))
 		} else {
 			c.writelnString(fmt.Sprintf("There is %s on the floor.", names))
@@ -160,8 +424,9 @@ func renderRoomDescription(c *connection, characterID model.CharacterID, room *m
 	}
 
 	// print exits
-	for direction, exit := range room.Exits {
-		if exit == nil {
+	for _, direction := range model.Directions {
+		exit := room.Exits[direction]
+		if exit == nil || exit.Hidden {
 			continue
 		}
 		target, err := c.sim.GetRoom(exit.WorldID, exit.RoomID)
@@ -169,8 +434,128 @@ func renderRoomDescription(c *connection, characterID model.CharacterID, room *m
 			logging.Error("Room not found")
 			continue
 		}
+		if exit.Door != nil && exit.Door.State != model.DoorStateOpen {
+			c.writelnString(fmt.Sprintf("%s - %s (%s door)", direction.String(), target.Name, exit.Door.State.String()))
+			continue
+		}
 		c.writelnString(fmt.Sprintf("%s - %s", direction.String(), target.Name))
 	}
+
+	// print named exits
+	var keywords []string
+	for keyword, exit := range room.NamedExits {
+		if exit.Hidden {
+			continue
+		}
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		exit := room.NamedExits[keyword]
+		target, err := c.sim.GetRoom(exit.WorldID, exit.RoomID)
+		if err != nil {
+			logging.Error("Room not found")
+			continue
+		}
+		c.writeln([]byte(fmt.Sprintf("%s - %s", styleCommand(keyword), target.Name)))
+	}
+}
+
+// renderMarkup styles text written with the asset markup, such as room and item descriptions, and wraps it to the client's width.
+func renderMarkup(c *connection, text string) {
+	parser := Parser{}
+	description, err := parser.Parse(text)
+	if err != nil {
+		logging.Error("Failed to parse description")
+		c.writeln(styleDescription(text))
+		return
+	}
+
+	var buf bytes.Buffer
+	for i := range description.Sections {
+		switch description.Sections[i].Type {
+		case SectionTypeCommand:
+			buf.Write(styleCommand(description.Sections[i].Text))
+		case SectionTypeSpeech:
+			buf.Write(styleSpeech(description.Sections[i].Text))
+		case SectionTypeHint:
+			buf.Write(styleHint(description.Sections[i].Text))
+		case SectionTypeDefault:
+			buf.Write(styleDescription(description.Sections[i].Text))
+		}
+	}
+	if c.willNAWS {
+		c.write([]byte(wrap(c.width, buf.String())))
+	} else {
+		c.write(buf.Bytes())
+	}
+}
+
+func renderExtraDescription(c *connection, evt model.EvtExtraDescription) {
+	renderMarkup(c, evt.Extra.Description)
+	c.writeln()
+}
+
+func renderItemDescription(c *connection, evt model.EvtItemDescription) {
+	definition := evt.Item.Definition
+	switch {
+	case definition.LongDescription != "":
+		renderMarkup(c, definition.LongDescription)
+		c.writeln()
+	case definition.ShortDescription != "":
+		renderMarkup(c, definition.ShortDescription)
+		c.writeln()
+	default:
+		c.writelnString(fmt.Sprintf("You see nothing special about %s.", definition.Name))
+	}
+
+	if evt.Item.Container != nil {
+		var itemNames []string
+		for _, item := range evt.Item.Container.Items() {
+			itemNames = append(itemNames, item.DisplayName())
+		}
+		if len(itemNames) > 0 {
+			names, _ := renderList(itemNames)
+			c.writelnString(fmt.Sprintf("It contains %s.", names))
+		} else {
+			c.writelnString("It is empty.")
+		}
+	}
+}
+
+func renderCharacterDescription(c *connection, evt model.EvtCharacterDescription) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString("You look yourself over.")
+	} else {
+		c.writelnString(fmt.Sprintf("You look at %s.", renderCharacter(evt.Character)))
+	}
+
+	if evt.Character.Description != "" {
+		c.writelnString(evt.Character.Description)
+	}
+
+	if !evt.Character.Awake {
+		c.writelnString("They are sleeping.")
+	}
+
+	var equipment []string
+	for _, item := range evt.Character.Rig.Items() {
+		if item.Definition.ShortDescription != "" {
+			equipment = append(equipment, item.Definition.ShortDescription)
+		} else {
+			equipment = append(equipment, item.Definition.Name)
+		}
+	}
+	if len(equipment) > 0 {
+		names, _ := renderList(equipment)
+		c.writelnString(fmt.Sprintf("They are equipped with %s.", names))
+	}
+}
+
+func renderNothingToLookAt(c *connection, evt model.EvtNothingToLookAt) {
+	c.writelnString(fmt.Sprintf("You do not see %s here.", evt.Target))
 }
 
 func renderCharacterWakesUp(c *connection, evt model.EvtCharacterWakesUp) {
@@ -181,6 +566,42 @@ func renderCharacterFallsAsleep(c *connection, evt model.EvtCharacterFallsAsleep
 	c.writelnString(fmt.Sprintf("%s has fallen asleep.", renderCharacter(evt.Character)))
 }
 
+func renderCharacterLinkdead(c *connection, evt model.EvtCharacterLinkdead) {
+	c.writelnString(fmt.Sprintf("%s stares blankly into space.", renderCharacter(evt.Character)))
+}
+
+func renderCharacterReconnects(c *connection, evt model.EvtCharacterReconnects) {
+	c.writelnString(fmt.Sprintf("%s snaps back to attention.", renderCharacter(evt.Character)))
+}
+
+func renderSessionTakenOver(c *connection) {
+	c.writelnString("Your character has been taken over by a new login. Goodbye.")
+}
+
+func renderServerShuttingDown(c *connection) {
+	c.writeln(styleAnnouncement("The server is shutting down. Your character will be saved. Goodbye."))
+}
+
+func renderShutdownScheduled(c *connection, evt model.EvtShutdownScheduled) {
+	if evt.In <= 0 {
+		c.writeln(styleAnnouncement(fmt.Sprintf("%s is shutting down the server now.", evt.Character.Name)))
+		return
+	}
+	c.writeln(styleAnnouncement(fmt.Sprintf("%s has scheduled the server to shut down in %s.", evt.Character.Name, evt.In)))
+}
+
+func renderShutdownCountdown(c *connection, evt model.EvtShutdownCountdown) {
+	c.writeln(styleAnnouncement(fmt.Sprintf("The server will shut down in %s.", evt.In)))
+}
+
+func renderShutdownCancelled(c *connection, evt model.EvtShutdownCancelled) {
+	c.writeln(styleAnnouncement(fmt.Sprintf("%s has cancelled the shutdown.", evt.Character.Name)))
+}
+
+func renderNoShutdownScheduled(c *connection) {
+	c.writelnString("There is no shutdown to cancel.")
+}
+
 func renderNarration(c *connection, evt model.EvtNarration) {
 	c.writeln(rgbterm.FgBytes([]byte(evt.Content), 0, 255, 255))
 }
@@ -197,24 +618,130 @@ func renderCharacterSpeaks(c *connection, evt model.EvtCharacterSpeaks) {
 }
 
 func renderCharacterArrives(c *connection, evt model.EvtCharacterArrives) {
-	c.writelnString(fmt.Sprintf("%s arrives from the %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	switch {
+	case evt.Teleported:
+		c.writelnString(fmt.Sprintf("%s appears out of thin air.", renderCharacter(evt.Character)))
+	case !evt.Named:
+		c.writelnString(fmt.Sprintf("%s arrives from the %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	case evt.Via != "":
+		c.writelnString(fmt.Sprintf("%s arrives through %s.", renderCharacter(evt.Character), evt.Via))
+	default:
+		c.writelnString(fmt.Sprintf("%s arrives.", renderCharacter(evt.Character)))
+	}
 }
 
 func renderCharacterLeaves(c *connection, evt model.EvtCharacterLeaves) {
+	if evt.Teleported {
+		c.writelnString(fmt.Sprintf("%s vanishes.", renderCharacter(evt.Character)))
+		return
+	}
+	if evt.Named {
+		c.writelnString(fmt.Sprintf("%s leaves through %s.", renderCharacter(evt.Character), evt.Via))
+		return
+	}
 	c.writelnString(fmt.Sprintf("%s leaves to the %s.", renderCharacter(evt.Character), evt.Direction.String()))
 }
 
+func renderExitRevealed(c *connection, evt model.EvtExitRevealed) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	switch {
+	case evt.Character == nil:
+		c.writeln([]byte(fmt.Sprintf("A hidden exit is revealed: %s.", styleCommand(evt.Label))))
+	case evt.Character.ID == characterID:
+		c.writeln([]byte(fmt.Sprintf("You find a hidden exit: %s.", styleCommand(evt.Label))))
+	default:
+		c.writeln([]byte(fmt.Sprintf("%s finds a hidden exit: %s.", renderCharacter(evt.Character), styleCommand(evt.Label))))
+	}
+}
+
+func renderYouFindNothing(c *connection) {
+	c.writelnString("You search but find nothing.")
+}
+
+func renderExitRefuses(c *connection, evt model.EvtExitRefuses) {
+	if evt.Message == "" {
+		renderNoExitInThatDirection(c)
+		return
+	}
+	c.writelnString(evt.Message)
+}
+
 func renderNoExitInThatDirection(c *connection) {
 	c.writelnString("You cannot go that way.")
 }
 
+func renderCharacterOpensDoor(c *connection, evt model.EvtCharacterOpensDoor) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You open the door to the %s.", evt.Direction.String()))
+	} else {
+		c.writelnString(fmt.Sprintf("%s opens the door to the %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	}
+}
+
+func renderCharacterClosesDoor(c *connection, evt model.EvtCharacterClosesDoor) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You close the door to the %s.", evt.Direction.String()))
+	} else {
+		c.writelnString(fmt.Sprintf("%s closes the door to the %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	}
+}
+
+func renderCharacterLocksDoor(c *connection, evt model.EvtCharacterLocksDoor) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You lock the door to the %s.", evt.Direction.String()))
+	} else {
+		c.writelnString(fmt.Sprintf("%s locks the door to the %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	}
+}
+
+func renderCharacterUnlocksDoor(c *connection, evt model.EvtCharacterUnlocksDoor) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You unlock the door to the %s.", evt.Direction.String()))
+	} else {
+		c.writelnString(fmt.Sprintf("%s unlocks the door to the %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	}
+}
+
+func renderDoorChanges(c *connection, evt model.EvtDoorChanges) {
+	c.writelnString(fmt.Sprintf("The door to the %s is now %s.", evt.Direction.String(), evt.State.String()))
+}
+
+func renderDoorIs(c *connection, evt model.EvtDoorIs) {
+	c.writelnString(fmt.Sprintf("The door to the %s is %s.", evt.Direction.String(), evt.State.String()))
+}
+
+func renderDoorBlocksExit(c *connection, evt model.EvtDoorBlocksExit) {
+	c.writelnString(fmt.Sprintf("The door to the %s is %s.", evt.Direction.String(), evt.State.String()))
+}
+
+func renderNoDoorInThatDirection(c *connection) {
+	c.writelnString("There is no door that way.")
+}
+
+func renderDoorHasNoLock(c *connection) {
+	c.writelnString("That door has no lock.")
+}
+
+func renderYouDoNotHaveTheKey(c *connection) {
+	c.writelnString("You do not have the key.")
+}
+
 func renderCharacterTakesItem(c *connection, evt model.EvtCharacterTakesItem) {
 	characterID := CharacterIDFromContext(c.ctx)
 
 	if evt.Character.ID == characterID {
-		c.writelnString(fmt.Sprintf("You take %s.", evt.Item.Definition.Name))
+		c.writelnString(fmt.Sprintf("You take %s.", evt.Item.DisplayName()))
 	} else {
-		c.writelnString(fmt.Sprintf("%s takes %s.", renderCharacter(evt.Character), evt.Item.Definition.Name))
+		c.writelnString(fmt.Sprintf("%s takes %s.", renderCharacter(evt.Character), evt.Item.DisplayName()))
 	}
 }
 
@@ -222,9 +749,9 @@ func renderCharacterDropsItem(c *connection, evt model.EvtCharacterDropsItem) {
 	characterID := CharacterIDFromContext(c.ctx)
 
 	if evt.Character.ID == characterID {
-		c.writelnString(fmt.Sprintf("You drop %s.", evt.Item.Definition.Name))
+		c.writelnString(fmt.Sprintf("You drop %s.", evt.Item.DisplayName()))
 	} else {
-		c.writelnString(fmt.Sprintf("%s drops %s on the ground.", renderCharacter(evt.Character), evt.Item.Definition.Name))
+		c.writelnString(fmt.Sprintf("%s drops %s on the ground.", renderCharacter(evt.Character), evt.Item.DisplayName()))
 	}
 }
 
@@ -249,57 +776,695 @@ func renderCharacterUnequipsItem(c *connection, evt model.EvtCharacterUnequipsIt
 }
 
 func renderInventoryDescription(c *connection, evt model.EvtInventoryDescription) {
-	c.writeString("Backpack: ")
-	if evt.Character.Rig.Backpack != nil {
-		c.writelnString(evt.Character.Rig.Backpack.Definition.Name)
-	} else {
-		c.writelnString("none")
+	for _, slot := range model.RigSlots {
+		c.writeString(fmt.Sprintf("%-10s ", renderRigSlot(slot)+":"))
+		item := evt.Character.Rig.Slot(slot)
+		switch {
+		case item == nil:
+			c.writelnString("none")
+		case item.Definition.RigSlot != slot:
+			// the item lives in another slot and is taking this one up too
+			c.writelnString(fmt.Sprintf("(%s)", item.Definition.Name))
+		default:
+			c.writelnString(item.Definition.Name)
+		}
 	}
 
+	c.writelnString("")
+	c.writelnString(fmt.Sprintf("Money: %s", renderMoney(evt.Character.Money)))
 	c.writelnString("")
 
 	for _, v := range evt.Character.Container.Items() {
-		c.writelnString(fmt.Sprintf("%s    %.2fkg", v.Definition.Name, float64(v.Definition.Weight)/1000.0))
+		c.writelnString(fmt.Sprintf("%s    %.2fkg", v.DisplayName(), float64(v.Weight())/1000.0))
 	}
 }
 
-func renderYouAreNotWearing(c *connection, alias string) {
-	c.writelnString(fmt.Sprintf("You are not wearing %s.", alias))
+func renderItemNotEquipable(c *connection, item *model.Item) {
+	c.writelnString(fmt.Sprintf("You cannot equip %s.", item.Definition.Name))
 }
 
-func renderItemPutIntoStorage(c *connection, item *model.Item) {
-	c.writelnString(fmt.Sprintf("You put %s into your inventory.", item.Definition.Name))
+func renderShopList(c *connection, evt model.EvtShopList) {
+	c.writeln(styleLocation(evt.Shop.Name, ""))
+	if len(evt.Entries) == 0 {
+		c.writelnString(fmt.Sprintf("%s has nothing for sale.", renderKeeper(evt.Shop)))
+		return
+	}
+
+	for _, entry := range evt.Entries {
+		c.writelnString(fmt.Sprintf("%-30s %4d in stock    %s", entry.Definition.Name, entry.Count, renderMoney(entry.Price)))
+	}
 }
 
-func renderAdminSpawnsItem(c *connection, evt model.EvtAdminSpawnsItem) {
+func renderItemValue(c *connection, evt model.EvtItemValue) {
+	c.writelnString(fmt.Sprintf("%s would pay %s for %s.", renderKeeper(evt.Shop), renderMoney(evt.Price), evt.Item.Definition.Name))
+}
+
+func renderCharacterBuysItem(c *connection, evt model.EvtCharacterBuysItem) {
 	characterID := CharacterIDFromContext(c.ctx)
 
 	if evt.Character.ID == characterID {
-		c.writelnString(fmt.Sprintf("You spawn %s.", evt.Item.Definition.Name))
+		c.writelnString(fmt.Sprintf("You buy %s for %s.", evt.Item.DisplayName(), renderMoney(evt.Price)))
 	} else {
-		c.writelnString(fmt.Sprintf("%s spawns %s.", renderCharacter(evt.Character), evt.Item.Definition.Name))
+		c.writelnString(fmt.Sprintf("%s buys %s.", renderCharacter(evt.Character), evt.Item.DisplayName()))
 	}
 }
 
-func renderItemNotHere(c *connection) {
-	c.writelnString("There is no item by that name.")
+func renderCharacterSellsItem(c *connection, evt model.EvtCharacterSellsItem) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You sell %s for %s.", evt.Item.DisplayName(), renderMoney(evt.Price)))
+	} else {
+		c.writelnString(fmt.Sprintf("%s sells %s.", renderCharacter(evt.Character), evt.Item.DisplayName()))
+	}
 }
 
-func renderNoSpaceToTakeItem(c *connection) {
-	c.writelnString("You have no where to put that item.")
+func renderShopDoesNotSell(c *connection, evt model.EvtShopDoesNotSell) {
+	c.writelnString(fmt.Sprintf("%s does not have any %s for sale.", renderKeeper(evt.Shop), evt.Alias))
 }
 
-func renderNoSpaceToStoreItem(c *connection) {
-	c.writelnString("You have no where to put that item.")
+func renderShopWillNotBuy(c *connection, evt model.EvtShopWillNotBuy) {
+	c.writelnString(fmt.Sprintf("%s is not interested in %s.", renderKeeper(evt.Shop), evt.Item.Definition.Name))
 }
 
-func renderCannotPerformAction(c *connection) {
-	c.writelnString("You cannot do that.")
+func renderCannotAfford(c *connection, evt model.EvtCannotAfford) {
+	c.writelnString(fmt.Sprintf("You cannot afford that, it costs %s.", renderMoney(evt.Price)))
 }
 
-func renderList(items []string) (string, bool) {
-	l := len(items)
-	plural := l > 1
+func renderNoShopHere(c *connection) {
+	c.writelnString("There is no shop here.")
+}
+
+func renderCharacterCrafts(c *connection, evt model.EvtCharacterCrafts) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	var itemNames []string
+	for _, item := range evt.Items {
+		itemNames = append(itemNames, item.DisplayName())
+	}
+	names, _ := renderList(itemNames)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You craft %s.", names))
+	} else {
+		c.writelnString(fmt.Sprintf("%s crafts %s.", renderCharacter(evt.Character), names))
+	}
+}
+
+func renderCraftingNeedsRoom(c *connection, evt model.EvtCraftingNeedsRoom) {
+	c.writelnString(fmt.Sprintf("You need to be somewhere with a %s to craft %s.", evt.Tag, evt.Recipe.Name))
+}
+
+func renderCraftingNeedsTool(c *connection, evt model.EvtCraftingNeedsTool) {
+	c.writelnString(fmt.Sprintf("You need %s to craft %s.", evt.Tool.Name, evt.Recipe.Name))
+}
+
+func renderCraftingNeedsInput(c *connection, evt model.EvtCraftingNeedsInput) {
+	name := evt.Input.Name
+	if evt.Quantity > 1 {
+		name = fmt.Sprintf("%d %s", evt.Quantity, evt.Input.Plural())
+	}
+	c.writelnString(fmt.Sprintf("You need %s to craft %s.", name, evt.Recipe.Name))
+}
+
+func renderQuestStarted(c *connection, evt model.EvtQuestStarted) {
+	c.writeln(styleQuest("New quest: " + evt.Quest.Name))
+	if evt.Quest.Description != "" {
+		renderMarkup(c, evt.Quest.Description)
+	}
+	if step := evt.Quest.CurrentStep(&model.QuestProgress{}); step != nil {
+		c.writelnString(renderQuestStep(step))
+	}
+}
+
+func renderQuestAdvanced(c *connection, evt model.EvtQuestAdvanced) {
+	c.writeln(styleQuest("Quest updated: " + evt.Quest.Name))
+	c.writelnString(renderQuestStep(evt.Step))
+}
+
+func renderQuestCompleted(c *connection, evt model.EvtQuestCompleted) {
+	c.writeln(styleQuest("Quest completed: " + evt.Quest.Name))
+
+	var rewards []string
+	if evt.Money > 0 {
+		rewards = append(rewards, renderMoney(evt.Money))
+	}
+	for _, item := range evt.Items {
+		rewards = append(rewards, item.DisplayName())
+	}
+	if names, _ := renderList(rewards); names != "" {
+		c.writelnString(fmt.Sprintf("You receive %s.", names))
+	}
+}
+
+func renderQuestLog(c *connection, evt model.EvtQuestLog) {
+	if len(evt.Quests) == 0 {
+		c.writelnString("You are not on any quests.")
+		return
+	}
+
+	for _, quest := range evt.Quests {
+		progress := evt.Character.Quests[quest.ID]
+		if progress.Completed {
+			c.writelnString(fmt.Sprintf("%-30s completed", quest.Name))
+			continue
+		}
+		c.writelnString(fmt.Sprintf("%-30s step %d of %d", quest.Name, progress.Step+1, len(quest.Steps)))
+	}
+}
+
+func renderQuestDetails(c *connection, evt model.EvtQuestDetails) {
+	c.writeln(styleQuest(evt.Quest.Name))
+	if evt.Quest.Description != "" {
+		renderMarkup(c, evt.Quest.Description)
+	}
+
+	for i, step := range evt.Quest.Steps {
+		switch {
+		case evt.Progress.Completed || i < evt.Progress.Step:
+			c.writelnString(fmt.Sprintf("  [x] %s", renderQuestStep(step)))
+		case i == evt.Progress.Step:
+			c.writelnString(fmt.Sprintf("  [ ] %s", renderQuestStep(step)))
+		}
+	}
+}
+
+// renderQuestStep uses the builder's description of the step, or describes it from its goal.
+func renderQuestStep(step *model.QuestStep) string {
+	if step.Description != "" {
+		return step.Description
+	}
+
+	switch step.Type {
+	case model.QuestStepObtain:
+		if step.Quantity > 1 {
+			return fmt.Sprintf("Obtain %d %s.", step.Quantity, step.Item.Plural())
+		}
+		return fmt.Sprintf("Obtain %s.", step.Item.Name)
+	case model.QuestStepTalk:
+		return fmt.Sprintf("Talk to %s.", step.NPC)
+	default:
+		return "Continue your quest."
+	}
+}
+
+func renderNobodyToTalkTo(c *connection, evt model.EvtNobodyToTalkTo) {
+	if evt.Target == "" {
+		c.writelnString("There is nobody here to talk to.")
+		return
+	}
+	c.writelnString(fmt.Sprintf("There is no %s here to talk to.", evt.Target))
+}
+
+func renderTell(c *connection, evt model.EvtTell) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You tell %s: %q.", renderCharacter(evt.Target), evt.Content))
+	} else {
+		c.writelnString(fmt.Sprintf("%s tells you: %q.", renderCharacter(evt.Character), evt.Content))
+	}
+}
+
+func renderWhisper(c *connection, evt model.EvtWhisper) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You whisper to %s: %q.", renderCharacter(evt.Target), evt.Content))
+	} else {
+		c.writelnString(fmt.Sprintf("%s whispers to you: %q.", renderCharacter(evt.Character), evt.Content))
+	}
+}
+
+func renderShout(c *connection, evt model.EvtShout) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You shout: %q!", evt.Content))
+	} else {
+		c.writelnString(fmt.Sprintf("%s shouts: %q!", renderCharacter(evt.Character), evt.Content))
+	}
+}
+
+func renderEmote(c *connection, evt model.EvtEmote) {
+	c.writelnString(fmt.Sprintf("%s %s", renderCharacter(evt.Character), evt.Content))
+}
+
+func renderSocial(c *connection, evt model.EvtSocial) {
+	characterID := CharacterIDFromContext(c.ctx)
+	messages := evt.Social.Messages(evt.Character, evt.Target)
+
+	var template string
+	switch {
+	case evt.Character.ID == characterID:
+		template = messages.Actor
+	case evt.Target != nil && evt.Target.ID == characterID:
+		template = messages.Target
+	default:
+		template = messages.Others
+	}
+
+	if template == "" {
+		return
+	}
+
+	replacements := []string{"$n", renderCharacter(evt.Character)}
+	if evt.Target != nil {
+		replacements = append(replacements, "$N", renderCharacter(evt.Target))
+	}
+	c.writelnString(strings.NewReplacer(replacements...).Replace(template))
+}
+
+func renderNobodyToReplyTo(c *connection) {
+	c.writelnString("Nobody has sent you a tell to reply to.")
+}
+
+func renderChannelMessage(c *connection, evt model.EvtChannelMessage) {
+	c.writelnString(fmt.Sprintf("[%s] %s: %s", evt.Channel.Name, renderCharacter(evt.Character), evt.Content))
+}
+
+func renderChannelJoined(c *connection, evt model.EvtChannelJoined) {
+	c.writelnString(fmt.Sprintf("You join the %s channel.", evt.Channel.Name))
+	for _, message := range evt.History {
+		c.writelnString(fmt.Sprintf("[%s] %s %s: %s", evt.Channel.Name, message.Time.Format("15:04"), renderCharacter(message.Character), message.Content))
+	}
+}
+
+func renderChannelLeft(c *connection, evt model.EvtChannelLeft) {
+	c.writelnString(fmt.Sprintf("You leave the %s channel.", evt.Channel))
+}
+
+func renderNotInChannel(c *connection, evt model.EvtNotInChannel) {
+	c.writelnString(fmt.Sprintf("You are not in the %s channel.", evt.Channel))
+}
+
+func renderAlreadyInChannel(c *connection, evt model.EvtAlreadyInChannel) {
+	c.writelnString(fmt.Sprintf("You are already in the %s channel.", evt.Channel))
+}
+
+func renderChannelList(c *connection, evt model.EvtChannelList) {
+	if len(evt.Channels) == 0 {
+		c.writelnString(fmt.Sprintf("You are not in any channels. Type %s to join one.", styleCommand("channel join <name>")))
+		return
+	}
+
+	names, _ := renderList(evt.Channels)
+	c.writelnString(fmt.Sprintf("You are in %s.", names))
+}
+
+func renderCharacterFollows(c *connection, evt model.EvtCharacterFollows) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	switch characterID {
+	case evt.Character.ID:
+		c.writelnString(fmt.Sprintf("You start following %s.", renderCharacter(evt.Target)))
+	case evt.Target.ID:
+		c.writelnString(fmt.Sprintf("%s starts following you.", renderCharacter(evt.Character)))
+	default:
+		c.writelnString(fmt.Sprintf("%s starts following %s.", renderCharacter(evt.Character), renderCharacter(evt.Target)))
+	}
+}
+
+func renderCharacterStopsFollowing(c *connection, evt model.EvtCharacterStopsFollowing) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	switch characterID {
+	case evt.Character.ID:
+		c.writelnString(fmt.Sprintf("You stop following %s.", renderCharacter(evt.Target)))
+	case evt.Target.ID:
+		c.writelnString(fmt.Sprintf("%s stops following you.", renderCharacter(evt.Character)))
+	default:
+		c.writelnString(fmt.Sprintf("%s stops following %s.", renderCharacter(evt.Character), renderCharacter(evt.Target)))
+	}
+}
+
+func renderYouFollow(c *connection, evt model.EvtYouFollow) {
+	c.writelnString(fmt.Sprintf("You follow %s.", renderCharacter(evt.Target)))
+}
+
+func renderNotFollowing(c *connection) {
+	c.writelnString("You are not following anyone.")
+}
+
+func renderCharacterNotFound(c *connection, evt model.EvtCharacterNotFound) {
+	c.writelnString(fmt.Sprintf("Nobody called %s can be found.", evt.Name))
+}
+
+func renderGroupInvite(c *connection, evt model.EvtGroupInvite) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You invite %s to join your group.", renderCharacter(evt.Target)))
+	} else {
+		c.writelnString(fmt.Sprintf("%s invites you to join their group. Type %s to join.", renderCharacter(evt.Character), styleCommand("group accept")))
+	}
+}
+
+func renderGroupJoined(c *connection, evt model.EvtGroupJoined) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You join %s's group.", renderCharacter(evt.Group.Leader)))
+	} else {
+		c.writelnString(fmt.Sprintf("%s joins the group.", renderCharacter(evt.Character)))
+	}
+}
+
+func renderGroupLeft(c *connection, evt model.EvtGroupLeft) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	switch {
+	case evt.Character.ID == characterID && evt.Kicked:
+		c.writelnString("You have been removed from the group.")
+		return
+	case evt.Character.ID == characterID:
+		c.writelnString("You leave the group.")
+		return
+	case evt.Kicked:
+		c.writelnString(fmt.Sprintf("%s has been removed from the group.", renderCharacter(evt.Character)))
+	default:
+		c.writelnString(fmt.Sprintf("%s leaves the group.", renderCharacter(evt.Character)))
+	}
+
+	if evt.NewLeader != nil {
+		c.writelnString(fmt.Sprintf("%s now leads the group.", renderCharacter(evt.NewLeader)))
+	}
+}
+
+func renderGroupList(c *connection, evt model.EvtGroupList) {
+	for _, member := range evt.Group.Members {
+		line := renderCharacter(member)
+		if member == evt.Group.Leader {
+			line += " (leader)"
+		}
+		if !member.Awake {
+			line += " (asleep)"
+		}
+		c.writelnString(line)
+	}
+}
+
+func renderAlreadyInGroup(c *connection, evt model.EvtAlreadyInGroup) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString("You are already in a group.")
+	} else {
+		c.writelnString(fmt.Sprintf("%s is already in a group.", renderCharacter(evt.Character)))
+	}
+}
+
+func renderNotInGroup(c *connection) {
+	c.writelnString("You are not in a group.")
+}
+
+func renderNotGroupLeader(c *connection) {
+	c.writelnString("Only the leader of the group can do that.")
+}
+
+func renderNoGroupInvite(c *connection) {
+	c.writelnString("Nobody has invited you to join their group.")
+}
+
+func renderPartyChat(c *connection, evt model.EvtPartyChat) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("[party] You say: %q.", evt.Content))
+	} else {
+		c.writelnString(fmt.Sprintf("[party] %s says: %q.", renderCharacter(evt.Character), evt.Content))
+	}
+}
+
+func renderWho(c *connection, evt model.EvtWho) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	// work out which group the viewer is in so their party can be marked
+	var group *model.Group
+	for _, ch := range evt.Characters {
+		if ch.ID == characterID {
+			group = ch.Group
+		}
+	}
+
+	c.writelnString(fmt.Sprintf("%d playing:", len(evt.Characters)))
+	for _, ch := range evt.Characters {
+		line := renderCharacter(ch)
+		switch {
+		case ch.Group == nil:
+		case ch.Group == group && ch.Group.Leader == ch:
+			line += " [your group, leader]"
+		case ch.Group == group:
+			line += " [your group]"
+		case ch.Group.Leader == ch:
+			line += fmt.Sprintf(" [group leader, %d members]", len(ch.Group.Members))
+		default:
+			line += fmt.Sprintf(" [in %s's group]", ch.Group.Leader.Name)
+		}
+		c.writelnString(line)
+	}
+}
+
+func renderYouAreNotWearing(c *connection, alias string) {
+	c.writelnString(fmt.Sprintf("You are not wearing %s.", alias))
+}
+
+func renderItemPutIntoStorage(c *connection, item *model.Item) {
+	c.writelnString(fmt.Sprintf("You put %s into your inventory.", item.Definition.Name))
+}
+
+func renderAdminSpawnsItem(c *connection, evt model.EvtAdminSpawnsItem) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You spawn %s.", evt.Item.Definition.Name))
+	} else {
+		c.writelnString(fmt.Sprintf("%s spawns %s.", renderCharacter(evt.Character), evt.Item.Definition.Name))
+	}
+}
+
+func renderAdminTeleports(c *connection, evt model.EvtAdminTeleports) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	switch {
+	case evt.Admin.ID == characterID && evt.Character.ID == characterID:
+		// the room description is enough
+	case evt.Admin.ID == characterID:
+		c.writelnString(fmt.Sprintf("You move %s to %s.", renderCharacter(evt.Character), evt.Room.Name))
+	default:
+		c.writelnString(fmt.Sprintf("%s has moved you.", renderCharacter(evt.Admin)))
+	}
+}
+
+func renderAdminRoomNotFound(c *connection, evt model.EvtAdminRoomNotFound) {
+	c.writelnString(fmt.Sprintf("There is no room %d in world '%s'.", evt.RoomID, evt.WorldID))
+}
+
+func renderAdminKicks(c *connection, evt model.EvtAdminKicks) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You have been disconnected by %s. Goodbye.", renderCharacter(evt.Admin)))
+	} else {
+		c.writelnString(fmt.Sprintf("You disconnect %s.", renderCharacter(evt.Character)))
+	}
+}
+
+func renderAdminCannotKick(c *connection, evt model.EvtAdminCannotKick) {
+	c.writelnString(fmt.Sprintf("You can't disconnect %s.", renderCharacter(evt.Character)))
+}
+
+func renderAdminTargetNotFound(c *connection, evt model.EvtAdminTargetNotFound) {
+	c.writelnString(fmt.Sprintf("There is no room, character or item called '%s'.", evt.Target))
+}
+
+func renderAdminInspectCharacter(c *connection, evt model.EvtAdminInspectCharacter) {
+	ch := evt.Character
+
+	c.writelnString(fmt.Sprintf("Character %s", renderCharacter(ch)))
+	c.writelnString(fmt.Sprintf("  id:          %s", ch.ID))
+	c.writelnString(fmt.Sprintf("  room:        %s %d (%s)", ch.Room.WorldID, ch.Room.ID, ch.Room.Name))
+	c.writelnString(fmt.Sprintf("  awake:       %t", ch.Awake))
+	c.writelnString(fmt.Sprintf("  linkdead:    %t", ch.Linkdead))
+	c.writelnString(fmt.Sprintf("  money:       %d", ch.Money))
+	c.writelnString(fmt.Sprintf("  attributes:  str %d, dex %d, con %d, int %d", ch.Attributes.Strength, ch.Attributes.Dexterity, ch.Attributes.Constitution, ch.Attributes.Intelligence))
+	c.writelnString(fmt.Sprintf("  background:  %d", ch.Background))
+	c.writelnString(fmt.Sprintf("  description: %s", ch.Description))
+	if ch.Group != nil {
+		c.writelnString(fmt.Sprintf("  group:       %s led by %s", ch.Group.ID, ch.Group.Leader.Name))
+	}
+
+	var flags []string
+	for flag, set := range ch.Flags {
+		if set {
+			flags = append(flags, flag)
+		}
+	}
+	sort.Strings(flags)
+	c.writelnString(fmt.Sprintf("  flags:       %s", strings.Join(flags, ", ")))
+
+	c.writelnString("  rig:")
+	for _, item := range ch.Rig.Items() {
+		renderItemTree(c, item, 2)
+	}
+
+	c.writelnString(fmt.Sprintf("  inventory (container %s):", ch.Container.ID()))
+	renderContainerTree(c, ch.Container, 2)
+}
+
+func renderAdminInspectRoom(c *connection, evt model.EvtAdminInspectRoom) {
+	room := evt.Room
+
+	c.writelnString(fmt.Sprintf("Room %s %d: %s", room.WorldID, room.ID, room.Name))
+	if world := evt.World; world != nil {
+		c.writelnString(fmt.Sprintf("  world:  %s (spawn %d, recall %d, levels %d-%d)", world.Name, world.SpawnRoom, world.RecallRoom, world.MinLevel, world.MaxLevel))
+	}
+	c.writelnString(fmt.Sprintf("  region: %s", room.Region))
+	c.writelnString(fmt.Sprintf("  alone:  %t", room.Alone))
+	c.writelnString(fmt.Sprintf("  shop:   %d", room.ShopID))
+	c.writelnString(fmt.Sprintf("  tags:   %s", strings.Join(room.Tags, ", ")))
+
+	c.writelnString("  exits:")
+	for _, direction := range model.Directions {
+		if exit := room.Exits[direction]; exit != nil {
+			c.writelnString(fmt.Sprintf("    %s -> %s %d", direction.String(), exit.WorldID, exit.RoomID))
+		}
+	}
+	for keyword, exit := range room.NamedExits {
+		c.writelnString(fmt.Sprintf("    %s -> %s %d", keyword, exit.WorldID, exit.RoomID))
+	}
+
+	c.writelnString("  characters:")
+	for _, ch := range room.Characters {
+		c.writelnString(fmt.Sprintf("    %s %s (awake: %t)", ch.ID, ch.Name, ch.Awake))
+	}
+
+	c.writelnString(fmt.Sprintf("  floor (container %s):", room.Container.ID()))
+	renderContainerTree(c, room.Container, 2)
+}
+
+func renderAdminInspectItem(c *connection, evt model.EvtAdminInspectItem) {
+	item := evt.Item
+
+	c.writelnString(fmt.Sprintf("Item %s", item.DisplayName()))
+	c.writelnString(fmt.Sprintf("  id:         %s", item.ID))
+	c.writelnString(fmt.Sprintf("  definition: %d %s", item.Definition.ID, item.Definition.Name))
+	c.writelnString(fmt.Sprintf("  quantity:   %d", item.Quantity))
+	c.writelnString(fmt.Sprintf("  weight:     %dg", item.Weight()))
+	c.writelnString(fmt.Sprintf("  value:      %d", item.Definition.Value))
+	if item.Container != nil {
+		c.writelnString(fmt.Sprintf("  contents (container %s):", item.Container.ID()))
+		renderContainerTree(c, item.Container, 2)
+	}
+}
+
+// renderItemTree writes a line for the item, and then everything inside it indented underneath.
+func renderItemTree(c *connection, item *model.Item, depth int) {
+	c.writelnString(fmt.Sprintf("%s%s %s x%d (definition %d)", strings.Repeat("  ", depth), item.ID, item.Definition.Name, item.Quantity, item.Definition.ID))
+	if item.Container != nil {
+		renderContainerTree(c, item.Container, depth+1)
+	}
+}
+
+func renderContainerTree(c *connection, container model.Container, depth int) {
+	items := container.Items()
+	ids := make([]string, 0, len(items))
+	for id := range items {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		renderItemTree(c, items[model.ItemID(id)], depth)
+	}
+}
+
+func renderAdminSetsField(c *connection, evt model.EvtAdminSetsField) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Admin.ID == characterID {
+		c.writelnString(fmt.Sprintf("You set %s's %s to %s.", renderCharacter(evt.Character), evt.Field, evt.Value))
+	} else {
+		c.writelnString(fmt.Sprintf("%s set your %s to %s.", renderCharacter(evt.Admin), evt.Field, evt.Value))
+	}
+}
+
+func renderAdminSetFailed(c *connection, evt model.EvtAdminSetFailed) {
+	c.writelnString(fmt.Sprintf("Can't set %s: %s.", evt.Field, evt.Reason))
+}
+
+func renderAdminPurgesRoom(c *connection, evt model.EvtAdminPurgesRoom) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You purge %d items from the room.", evt.Count))
+	} else {
+		c.writelnString(fmt.Sprintf("%s waves a hand and everything on the floor disappears.", renderCharacter(evt.Character)))
+	}
+}
+
+func renderBuildFailed(c *connection, evt model.EvtBuildFailed) {
+	c.writelnString(fmt.Sprintf("Nothing changes: %s.", evt.Reason))
+}
+
+func renderBuilderDigs(c *connection, evt model.EvtBuilderDigs) {
+	characterID := CharacterIDFromContext(c.ctx)
+
+	if evt.Character.ID == characterID {
+		c.writelnString(fmt.Sprintf("You dig %s to %s (room %d).", evt.Direction.String(), evt.Room.Name, evt.Room.ID))
+	} else {
+		c.writelnString(fmt.Sprintf("%s opens a new way %s.", renderCharacter(evt.Character), evt.Direction.String()))
+	}
+}
+
+func renderRoomEdited(c *connection, evt model.EvtRoomEdited) {
+	c.writelnString(fmt.Sprintf("You change the %s of %s.", evt.Field, evt.Room.Name))
+}
+
+func renderExitLinked(c *connection, evt model.EvtExitLinked) {
+	c.writelnString(fmt.Sprintf("The exit %s now leads to room %d in world '%s'.", evt.Direction.String(), evt.RoomID, evt.WorldID))
+}
+
+func renderExitUnlinked(c *connection, evt model.EvtExitUnlinked) {
+	c.writelnString(fmt.Sprintf("You remove the exit %s.", evt.Direction.String()))
+}
+
+func renderItemDefinitionCreated(c *connection, evt model.EvtItemDefinitionCreated) {
+	c.writelnString(fmt.Sprintf("You create item %d, %s.", evt.Definition.ID, evt.Definition.Name))
+}
+
+func renderItemDefinitionEdited(c *connection, evt model.EvtItemDefinitionEdited) {
+	c.writelnString(fmt.Sprintf("You change the %s of item %d, %s.", evt.Field, evt.Definition.ID, evt.Definition.Name))
+}
+
+func renderDataReloaded(c *connection) {
+	c.writelnString("You reload the data folder.")
+}
+
+func renderDataReloadFailed(c *connection, evt model.EvtDataReloadFailed) {
+	c.write([]byte(evt.Reason))
+	c.writeln()
+}
+
+func renderItemNotHere(c *connection) {
+	c.writelnString("There is no item by that name.")
+}
+
+func renderNoSpaceToTakeItem(c *connection) {
+	c.writelnString("You have no where to put that item.")
+}
+
+func renderNoSpaceToStoreItem(c *connection) {
+	c.writelnString("You have no where to put that item.")
+}
+
+func renderCannotPerformAction(c *connection) {
+	c.writelnString("You cannot do that.")
+}
+
+func renderList(items []string) (string, bool) {
+	l := len(items)
+	plural := l > 1
 
 	switch l {
 	case 0:
@@ -313,6 +1478,37 @@ func renderList(items []string) (string, bool) {
 	}
 }
 
+func renderRigSlot(slot model.RigSlot) string {
+	switch slot {
+	case model.RigSlotMainHand:
+		return "Main hand"
+	case model.RigSlotOffHand:
+		return "Off hand"
+	default:
+		name := slot.String()
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+}
+
+func renderMoney(amount int64) string {
+	if amount == 1 {
+		return "1 coin"
+	}
+	return fmt.Sprintf("%d coins", amount)
+}
+
+// renderKeeper names whoever is running the shop, falling back to the shop itself.
+func renderKeeper(shop *model.Shop) string {
+	if shop.Keeper != "" {
+		return string(rgbterm.FgBytes([]byte(shop.Keeper), 150, 150, 255))
+	}
+	return shop.Name
+}
+
 func renderCharacter(character *model.Character) string {
-	return string(rgbterm.FgBytes([]byte(character.Name), 150, 150, 255))
+	return renderName(character.Name)
+}
+
+func renderName(name string) string {
+	return string(rgbterm.FgBytes([]byte(name), 150, 150, 255))
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"net"
+	"sync/atomic"
 
 	"github.com/soupstoregames/coda-mud/config"
 	"github.com/soupstoregames/coda-mud/services"
@@ -48,15 +49,20 @@ type Server struct {
 	Addr         string
 	sim          *simulation.Simulation
 	usersManager *services.UsersManager
+	auditLog     *services.AuditLog
+
+	listener net.Listener
+	closing  atomic.Bool
 }
 
 // NewServer is a helper constructor for building a server.
-func NewServer(c *config.Config, sim *simulation.Simulation, usersManager *services.UsersManager) *Server {
+func NewServer(c *config.Config, sim *simulation.Simulation, usersManager *services.UsersManager, auditLog *services.AuditLog) *Server {
 	return &Server{
 		Addr:         fmt.Sprintf("%s:%s", c.Address, c.Port),
 		Config:       c,
 		sim:          sim,
 		usersManager: usersManager,
+		auditLog:     auditLog,
 	}
 }
 
@@ -71,10 +77,21 @@ func (server *Server) ListenAndServe() error {
 	if nil != err {
 		return err
 	}
+	server.listener = listener
 
 	return server.serve(listener)
 }
 
+// Close stops the server accepting new connections. ListenAndServe returns without an error once it has.
+// Connections that are already open are closed when their characters are put to sleep.
+func (server *Server) Close() error {
+	server.closing.Store(true)
+	if server.listener == nil {
+		return nil
+	}
+	return server.listener.Close()
+}
+
 func (server *Server) serve(listener net.Listener) error {
 	defer listener.Close()
 	logging.Debug(fmt.Sprintf("Listening at %q.", listener.Addr()))
@@ -82,6 +99,9 @@ func (server *Server) serve(listener net.Listener) error {
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
+			if server.closing.Load() {
+				return nil
+			}
 			return err
 		}
 
@@ -92,7 +112,7 @@ func (server *Server) serve(listener net.Listener) error {
 func (server *Server) handle(tcpConn net.Conn) {
 	connectionID := uuid.NewString()
 
-	c := newTelnetConnection(tcpConn, server.Config, server.sim, server.usersManager)
+	c := newTelnetConnection(tcpConn, server.Config, server.sim, server.usersManager, server.auditLog)
 	c.ctx = WithConnectionID(c.ctx, connectionID)
 
 	logger := logging.BuildConnectionLogger(connectionID)
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"github.com/aybabtme/rgbterm"
 	"github.com/soupstoregames/coda-mud/config"
+	"github.com/soupstoregames/coda-mud/services"
+	"github.com/soupstoregames/coda-mud/simulation"
 	"github.com/soupstoregames/coda-mud/simulation/model"
 	"github.com/soupstoregames/go-core/logging"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // state is the interface for all scenes in this package
@@ -116,7 +120,7 @@ func (s *stateLogin) handleInput(input byte) error {
 			s.password = s.input.String()
 			s.input.Reset()
 
-			characterID, ok := s.conn.usersManager.Login(s.username, s.password)
+			ok := s.conn.usersManager.Login(s.username, s.password)
 			if !ok {
 				s.conn.writelnString("Invalid login.")
 				s.attempts++
@@ -134,23 +138,12 @@ func (s *stateLogin) handleInput(input byte) error {
 				return nil
 			}
 
-			if characterID == ("") {
-				s.conn.state.Push(&stateCharacterCreation{
-					conn:     s.conn,
-					config:   s.config,
-					username: s.username,
-				})
-				s.conn.state.Peek().onEnter()
-
-				return nil
-			}
-
-			s.conn.ctx = WithCharacterID(s.conn.ctx, characterID)
-
+			s.conn.ctx = WithUsername(s.conn.ctx, s.username)
 			s.conn.state.Pop()
-			s.conn.state.Push(&stateWorld{
-				conn:   s.conn,
-				config: s.config,
+			s.conn.state.Push(&stateCharacterSelect{
+				conn:     s.conn,
+				config:   s.config,
+				username: s.username,
 			})
 			s.conn.state.Peek().onEnter()
 		}
@@ -181,6 +174,242 @@ func (s *stateLogin) writePrompt() {
 	}
 }
 
+// stateCharacterSelect lists the account's characters to play, create or delete
+type stateCharacterSelect struct {
+	config   *config.Config
+	conn     *connection
+	username string
+
+	characters []simulation.CharacterSummary
+	// deleting is set while the player picks a character to delete, and target once they have picked one
+	deleting bool
+	target   *simulation.CharacterSummary
+
+	// choices are typed as lines, so that there can be more than nine characters
+	input bytes.Buffer
+}
+
+func (s *stateCharacterSelect) onEnter() error {
+	s.deleting = false
+	s.target = nil
+	s.input.Reset()
+	s.characters = s.conn.sim.CharacterSummaries(s.conn.usersManager.Characters(s.username))
+
+	s.conn.writeln()
+	s.conn.writelnString("CHARACTERS")
+	s.conn.writeln()
+	if len(s.characters) == 0 {
+		s.conn.writelnString("You have no characters yet.")
+	}
+	for i, character := range s.characters {
+		line := fmt.Sprintf("%d: %s - %s", i+1, renderName(character.Name), string(styleLocation(character.Room, character.Region)))
+		if character.Playing {
+			line += " (playing)"
+		}
+		s.conn.writelnString(line)
+	}
+	s.conn.writeln()
+	if len(s.characters) < s.config.MaxCharacters {
+		s.conn.writelnString("N: Create a new character")
+	}
+	if len(s.characters) > 0 {
+		s.conn.writelnString("D: Delete a character")
+	}
+	s.conn.writelnString("0: Quit")
+	s.writePrompt()
+
+	return nil
+}
+
+func (s *stateCharacterSelect) onExit() error {
+	return nil
+}
+
+func (s *stateCharacterSelect) handleInput(input byte) error {
+	switch input {
+	case charCR:
+		// do nothing
+	case charNULL:
+		fallthrough
+	case charLF:
+		line := strings.ToLower(strings.TrimSpace(s.input.String()))
+		s.input.Reset()
+		s.conn.writeln()
+
+		if line == "" {
+			s.writePrompt()
+			return nil
+		}
+		return s.handleLine(line)
+	case charDELETE:
+		if s.input.Len() > 0 {
+			s.input.Truncate(s.input.Len() - 1)
+			s.conn.write([]byte{8, 32, 8})
+		}
+	default:
+		s.input.WriteByte(input)
+		s.conn.write([]byte{input})
+	}
+
+	return nil
+}
+
+func (s *stateCharacterSelect) handleLine(line string) error {
+	switch {
+	case s.target != nil:
+		switch line {
+		case "y", "yes":
+			s.deleteCharacter(s.target.ID)
+			return s.onEnter()
+		case "n", "no":
+			return s.onEnter()
+		}
+		s.writePrompt()
+
+	case s.deleting:
+		if line == "0" {
+			return s.onEnter()
+		}
+		if character := s.pick(line); character != nil {
+			s.target = character
+		}
+		s.writePrompt()
+
+	default:
+		switch line {
+		case "0":
+			s.conn.close()
+		case "n":
+			if len(s.characters) >= s.config.MaxCharacters {
+				s.conn.writelnString(fmt.Sprintf("You can only have %d characters.", s.config.MaxCharacters))
+				s.writePrompt()
+				return nil
+			}
+			s.conn.state.Push(&stateCharacterCreation{
+				conn:     s.conn,
+				config:   s.config,
+				username: s.username,
+			})
+			s.conn.state.Peek().onEnter()
+		case "d":
+			if len(s.characters) == 0 {
+				s.writePrompt()
+				return nil
+			}
+			s.deleting = true
+			s.writePrompt()
+		default:
+			if character := s.pick(line); character != nil {
+				enterWorld(s.conn, s.config, character.ID)
+				return nil
+			}
+			s.writePrompt()
+		}
+	}
+
+	return nil
+}
+
+// pick returns the character with the number that was typed, or nil if there isn't one.
+func (s *stateCharacterSelect) pick(line string) *simulation.CharacterSummary {
+	i, err := strconv.Atoi(line)
+	if err != nil || i < 1 || i > len(s.characters) {
+		return nil
+	}
+	return &s.characters[i-1]
+}
+
+func (s *stateCharacterSelect) deleteCharacter(id model.CharacterID) {
+	if err := s.conn.sim.DeleteCharacter(id); err != nil {
+		if err == simulation.ErrCharacterAwake {
+			s.conn.writelnString("You cannot delete a character while it is in the world.")
+			return
+		}
+		logging.Error(err.Error())
+		s.conn.writelnString("Failed to delete character.")
+		return
+	}
+
+	if err := s.conn.usersManager.DisassociateCharacter(s.username, id); err != nil {
+		logging.Error(err.Error())
+	}
+
+	s.conn.writelnString("Character deleted.")
+}
+
+func (s *stateCharacterSelect) writePrompt() {
+	switch {
+	case s.target != nil:
+		s.conn.writeString(fmt.Sprintf("Delete %s forever? This cannot be undone. (y/n) ", s.target.Name))
+	case s.deleting:
+		s.conn.writeString("Delete which character? (0 to cancel) ")
+	default:
+		s.conn.write([]byte{charLF})
+		s.conn.write([]byte{'>', ' '})
+	}
+}
+
+// enterWorld puts the connection into the world as the character,
+// first asking whether to take over if someone is already playing it.
+func enterWorld(c *connection, conf *config.Config, characterID model.CharacterID) {
+	c.ctx = WithCharacterID(c.ctx, characterID)
+
+	if c.sim.CharacterPlaying(characterID) {
+		c.state.Push(&stateTakeover{
+			conn:   c,
+			config: conf,
+		})
+		c.state.Peek().onEnter()
+		return
+	}
+
+	c.state.Push(&stateWorld{
+		conn:   c,
+		config: conf,
+	})
+	c.state.Peek().onEnter()
+}
+
+// stateTakeover asks whether to take over a character that is being played from another connection
+type stateTakeover struct {
+	config *config.Config
+	conn   *connection
+}
+
+func (s *stateTakeover) onEnter() error {
+	s.conn.writelnString("That character is already playing.")
+	s.writePrompt()
+	return nil
+}
+
+func (s *stateTakeover) onExit() error {
+	return nil
+}
+
+func (s *stateTakeover) handleInput(input byte) error {
+	switch input {
+	case 'y', 'Y':
+		s.conn.writeln()
+		s.conn.state.Pop()
+		s.conn.state.Push(&stateWorld{
+			conn:     s.conn,
+			config:   s.config,
+			takeover: true,
+		})
+		s.conn.state.Peek().onEnter()
+	case 'n', 'N':
+		s.conn.writeln()
+		s.conn.state.Pop()
+		s.conn.state.Peek().onEnter()
+	}
+
+	return nil
+}
+
+func (s *stateTakeover) writePrompt() {
+	s.conn.writeString("Take over the other session? (y/n) ")
+}
+
 type stateRegister struct {
 	config *config.Config
 	conn   *connection
@@ -238,7 +467,9 @@ func (s *stateRegister) handleInput(input byte) error {
 				return nil
 			}
 
-			s.conn.state.Push(&stateCharacterCreation{
+			s.conn.ctx = WithUsername(s.conn.ctx, s.username)
+			s.conn.state.Pop()
+			s.conn.state.Push(&stateCharacterSelect{
 				conn:     s.conn,
 				config:   s.config,
 				username: s.username,
@@ -274,6 +505,22 @@ func (s *stateRegister) writePrompt() {
 	}
 }
 
+// character creation walks through each choice in turn, then shows a summary to confirm
+const (
+	creationName byte = iota
+	creationAttributes
+	creationBackground
+	creationDescription
+	creationConfirm
+)
+
+const (
+	baseAttribute       = 8
+	attributePoints     = 10
+	maxAttributePoints  = 5
+	maxDescriptionRunes = 240
+)
+
 type stateCharacterCreation struct {
 	config   *config.Config
 	conn     *connection
@@ -282,13 +529,18 @@ type stateCharacterCreation struct {
 	input bytes.Buffer
 	phase byte
 
-	name string
+	name        string
+	attributes  model.Attributes
+	backgrounds []*model.Background
+	background  *model.Background
+	description string
 }
 
 func (s *stateCharacterCreation) onEnter() error {
-	s.phase = 0
+	s.phase = creationName
+	s.backgrounds = s.conn.sim.Backgrounds()
 	s.conn.writelnString("CHARACTER CREATION")
-	s.conn.writelnString("What will you be known as?")
+	s.writeInstructions()
 	s.writePrompt()
 
 	return nil
@@ -298,6 +550,35 @@ func (s *stateCharacterCreation) onExit() error {
 	return nil
 }
 
+func (s *stateCharacterCreation) writeInstructions() {
+	switch s.phase {
+	case creationName:
+		s.conn.writelnString("What will you be known as? (3 to 16 letters)")
+	case creationAttributes:
+		s.conn.writelnString(fmt.Sprintf("Every attribute starts at %d. Share %d points between strength, dexterity, constitution and intelligence,", baseAttribute, attributePoints))
+		s.conn.writelnString(fmt.Sprintf("at most %d each, as four numbers. For example: 3 3 2 2", maxAttributePoints))
+	case creationBackground:
+		s.conn.writelnString("Where do you come from?")
+		for i, background := range s.backgrounds {
+			s.conn.writelnString(fmt.Sprintf("  [%d] %s - %s", i+1, background.Name, background.Description))
+		}
+	case creationDescription:
+		s.conn.writelnString("Describe what others see when they look at you.")
+	case creationConfirm:
+		s.conn.writelnString("")
+		s.conn.writelnString("Name:         " + renderName(s.name))
+		s.conn.writelnString(fmt.Sprintf("Strength:     %d", s.attributes.Strength))
+		s.conn.writelnString(fmt.Sprintf("Dexterity:    %d", s.attributes.Dexterity))
+		s.conn.writelnString(fmt.Sprintf("Constitution: %d", s.attributes.Constitution))
+		s.conn.writelnString(fmt.Sprintf("Intelligence: %d", s.attributes.Intelligence))
+		if s.background != nil {
+			s.conn.writelnString("Background:   " + s.background.Name)
+		}
+		s.conn.writelnString("Description:  " + s.description)
+		s.conn.writelnString("Create this character? (y/n)")
+	}
+}
+
 func (s *stateCharacterCreation) writePrompt() {
 	s.conn.write([]byte{charLF})
 	s.conn.write([]byte{'>', ' '})
@@ -311,19 +592,22 @@ func (s *stateCharacterCreation) handleInput(input byte) error {
 	case charNULL:
 		fallthrough
 	case charLF:
-		id := s.conn.sim.MakeCharacter(s.input.String())
-		if err := s.conn.usersManager.AssociateCharacter(s.username, id); err != nil {
-			return err
-		}
+		line := strings.TrimSpace(s.input.String())
+		s.input.Reset()
+		s.conn.writelnString("")
 
-		s.conn.ctx = WithCharacterID(s.conn.ctx, id)
+		// the key that opened character creation may be followed by a line ending
+		if line == "" && s.phase != creationDescription {
+			s.writePrompt()
+			return nil
+		}
 
-		s.conn.state.Pop()
-		s.conn.state.Push(&stateWorld{
-			conn:   s.conn,
-			config: s.config,
-		})
-		s.conn.state.Peek().onEnter()
+		if err := s.handleLine(line); err != nil {
+			s.conn.writelnString(rgbterm.String(err.Error(), 255, 100, 100, 0, 0, 0))
+		}
+		if s.conn.state.Peek() == s {
+			s.writePrompt()
+		}
 	case charDELETE:
 		if s.input.Len() > 0 {
 			s.input.Truncate(s.input.Len() - 1)
@@ -337,33 +621,148 @@ func (s *stateCharacterCreation) handleInput(input byte) error {
 	return nil
 }
 
+func (s *stateCharacterCreation) handleLine(line string) error {
+	switch s.phase {
+	case creationName:
+		if err := s.conn.sim.ValidateCharacterName(line); err != nil {
+			return err
+		}
+		s.name = strings.ToUpper(line[:1]) + strings.ToLower(line[1:])
+		s.phase = creationAttributes
+	case creationAttributes:
+		attributes, err := parseAttributes(line)
+		if err != nil {
+			return err
+		}
+		s.attributes = attributes
+		s.phase = creationBackground
+		if len(s.backgrounds) == 0 {
+			s.phase = creationDescription
+		}
+	case creationBackground:
+		i, err := strconv.Atoi(line)
+		if err != nil || i < 1 || i > len(s.backgrounds) {
+			return fmt.Errorf("pick a background from 1 to %d", len(s.backgrounds))
+		}
+		s.background = s.backgrounds[i-1]
+		s.phase = creationDescription
+	case creationDescription:
+		if line == "" {
+			return errors.New("say something about how you look")
+		}
+		if utf8.RuneCountInString(line) > maxDescriptionRunes {
+			return fmt.Errorf("descriptions can be at most %d characters long", maxDescriptionRunes)
+		}
+		s.description = line
+		s.phase = creationConfirm
+	case creationConfirm:
+		switch strings.ToLower(line) {
+		case "y", "yes":
+			return s.create()
+		case "n", "no":
+			s.phase = creationName
+			s.background = nil
+		default:
+			return errors.New("answer y or n")
+		}
+	}
+
+	s.writeInstructions()
+	return nil
+}
+
+func (s *stateCharacterCreation) create() error {
+	var backgroundID model.BackgroundID
+	if s.background != nil {
+		backgroundID = s.background.ID
+	}
+
+	id, err := s.conn.sim.MakeCharacter(s.name, s.description, s.attributes, backgroundID)
+	if err != nil {
+		// someone may have taken the name while we were deciding, so start again
+		s.phase = creationName
+		s.writeInstructions()
+		return err
+	}
+
+	if err := s.conn.usersManager.AssociateCharacter(s.username, id); err != nil {
+		return err
+	}
+
+	s.conn.state.Pop()
+	enterWorld(s.conn, s.config, id)
+	return nil
+}
+
+// parseAttributes reads the points to add to each attribute, in the order strength, dexterity, constitution, intelligence.
+func parseAttributes(line string) (model.Attributes, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 {
+		return model.Attributes{}, errors.New("enter four numbers, one for each attribute")
+	}
+
+	var points [4]int
+	total := 0
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 || n > maxAttributePoints {
+			return model.Attributes{}, fmt.Errorf("each attribute can have from 0 to %d points", maxAttributePoints)
+		}
+		points[i] = n
+		total += n
+	}
+
+	if total != attributePoints {
+		return model.Attributes{}, fmt.Errorf("you must spend exactly %d points, not %d", attributePoints, total)
+	}
+
+	return model.Attributes{
+		Strength:     baseAttribute + points[0],
+		Dexterity:    baseAttribute + points[1],
+		Constitution: baseAttribute + points[2],
+		Intelligence: baseAttribute + points[3],
+	}, nil
+}
+
 // stateWorld is the scene used interacting with the world
 type stateWorld struct {
 	config      *config.Config
 	conn        *connection
 	characterID model.CharacterID
+	// session is the token the simulation gave this connection for the character
+	session string
+	// takeover kicks off anyone else playing the character
+	takeover bool
 
 	input bytes.Buffer
 }
 
 // onEnter is called when the scene is first loaded
 func (s *stateWorld) onEnter() error {
-	s.conn.writelnString("You are in the world!\n\r")
-
 	s.characterID = CharacterIDFromContext(s.conn.ctx)
-	events, err := s.conn.sim.WakeUpCharacter(s.characterID)
+	events, session, err := s.conn.sim.WakeUpCharacter(s.characterID, s.takeover)
 	if err != nil {
+		if err == simulation.ErrCharacterAwake {
+			s.conn.writelnString("That character is already playing.")
+		}
+		s.conn.close()
 		return err
 	}
+	s.session = session
+
+	s.conn.writelnString("You are in the world!\n\r")
 
 	go renderEvents(s.conn, events)
 
 	return nil
 }
 
+// onExit leaves the character linkdead in the world if the connection closed without quitting.
 func (s *stateWorld) onExit() error {
 	logging.Debug("Disconnecting from world server")
-	// err := s.cc.Close()
+	if err := s.conn.sim.DisconnectCharacter(s.characterID, s.session); err != nil && err != simulation.ErrCharacterAsleep {
+		return err
+	}
 	logging.Debug("Disconnected from world server")
 	return nil
 }
@@ -390,24 +789,51 @@ func (s *stateWorld) handleInput(input byte) error {
 
 		// TODO: I dont like this - need to fix it
 		if commandText == "quit" {
+			if err := s.conn.sim.SleepCharacter(s.characterID, s.session); err != nil {
+				logging.Error(err.Error())
+			}
 			s.conn.close()
 			return errors.New("closed")
 		}
 
-		if commandText[0] == '@' {
-			// TODO: check admin
-		}
+		characterID := CharacterIDFromContext(s.conn.ctx)
 
 		command, ok := commands[commandText]
+		if ok && command.role != services.RolePlayer {
+			username := UsernameFromContext(s.conn.ctx)
+			allowed := s.conn.usersManager.Role(username).Allows(command.role)
+			if err := s.conn.auditLog.Record(username, characterID, cleansed, allowed); err != nil {
+				logging.Error(err.Error())
+			}
+
+			// pretend the command doesn't exist to anyone who can't use it
+			ok = allowed
+		}
 		if !ok {
+			// the player might have typed the keyword of a named exit, such as "climb ladder"
+			if err := moveThroughNamedExit(characterID, s.session, s.conn.sim, cleansed); err == nil {
+				return nil
+			}
+
+			// or the name of a chat channel they have joined, such as "newbie hello"
+			if s.conn.sim.InChannel(characterID, commandText) && len(tokens) > 1 {
+				if err := sayOnChannel(characterID, s.session, s.conn.sim, commandText, tokens[1:]); err == nil {
+					return nil
+				}
+			}
+
+			// or a social, such as "smile" or "wave bob"
+			if err := performSocial(characterID, s.session, s.conn.sim, commandText, tokens[1:]); err == nil {
+				return nil
+			}
+
 			echo := rgbterm.String("Huh?", 255, 100, 100, 0, 0, 0)
 			s.conn.writelnString(echo)
 			s.writePrompt()
 			return nil
 		}
 
-		characterID := CharacterIDFromContext(s.conn.ctx)
-		err := command(characterID, s.conn.sim, tokens[1:])
+		err := command.run(characterID, s.session, s.conn.sim, tokens[1:])
 		if err != nil {
 			echo := rgbterm.String(err.Error(), 255, 100, 100, 0, 0, 0)
 			s.conn.writelnString(echo)
@@ -18,6 +18,10 @@ func styleLocation(name, area string) []byte {
 	return rgbterm.FgBytes(buffer.Bytes(), 100, 255, 100)
 }
 
+func styleQuest(text string) []byte {
+	return rgbterm.FgBytes([]byte(text), 255, 215, 100)
+}
+
 func styleCommand(text string) []byte {
 	buffer := bytes.Buffer{}
 	buffer.WriteString("\033[1;4m")
@@ -99,3 +103,8 @@ func countANSIEscapeCodes(input []byte) int {
 
 	return totalLength
 }
+
+// styleAnnouncement is used for messages from the server to everyone playing.
+func styleAnnouncement(text string) []byte {
+	return rgbterm.FgBytes([]byte(text), 255, 100, 100)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// AuditLog records who ran privileged commands, and who tried to without permission.
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record writes a line for the command. Commands that were refused are marked as denied.
+func (a *AuditLog) Record(username string, characterID model.CharacterID, command string, allowed bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	outcome := "ran"
+	if !allowed {
+		outcome = "denied"
+	}
+
+	_, err := fmt.Fprintf(a.w, "%s %s user=%s character=%s command=%q\n", time.Now().UTC().Format(time.RFC3339), outcome, username, characterID, command)
+	return err
+}
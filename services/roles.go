@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role decides which commands a user may run. Each role can do everything the roles before it can.
+type Role int
+
+const (
+	RolePlayer Role = iota
+	RoleBuilder
+	RoleModerator
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RolePlayer:    "player",
+	RoleBuilder:   "builder",
+	RoleModerator: "moderator",
+	RoleAdmin:     "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Allows reports whether a user with this role may do something that needs the required role.
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole reads a role from its name. An empty name is a player.
+func ParseRole(name string) (Role, error) {
+	if name == "" {
+		return RolePlayer, nil
+	}
+
+	for role, n := range roleNames {
+		if strings.EqualFold(n, name) {
+			return role, nil
+		}
+	}
+
+	return RolePlayer, fmt.Errorf("unknown role '%s'", name)
+}
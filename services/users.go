@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"github.com/soupstoregames/coda-mud/simulation/data/state"
 	"github.com/soupstoregames/coda-mud/simulation/model"
 	"golang.org/x/crypto/bcrypt"
@@ -9,6 +10,8 @@ import (
 
 type UsersManager struct {
 	users map[string]User
+	// admins are always given the admin role, so there is a way to grant the first one
+	admins []string
 }
 
 func NewUsersManager() *UsersManager {
@@ -18,22 +21,97 @@ func NewUsersManager() *UsersManager {
 }
 
 type User struct {
-	username    string
-	password    []byte
-	characterID model.CharacterID
+	username     string
+	password     []byte
+	characterIDs []model.CharacterID
+	role         Role
 }
 
-func (u *UsersManager) Login(username, password string) (model.CharacterID, bool) {
+func (u *UsersManager) Login(username, password string) bool {
 	user, ok := u.users[username]
 	if !ok {
-		return "", false
+		return false
 	}
 
 	if err := bcrypt.CompareHashAndPassword(user.password, []byte(password)); err != nil {
-		return "", false
+		return false
 	}
 
-	return user.characterID, true
+	return true
+}
+
+// Characters returns the IDs of every character the user owns, in the order they were created.
+func (u *UsersManager) Characters(username string) []model.CharacterID {
+	user, ok := u.users[username]
+	if !ok {
+		return nil
+	}
+
+	characterIDs := make([]model.CharacterID, len(user.characterIDs))
+	copy(characterIDs, user.characterIDs)
+	return characterIDs
+}
+
+// Role returns the user's role. Users named in the admin list are always admins.
+func (u *UsersManager) Role(username string) Role {
+	for _, admin := range u.admins {
+		if admin == username {
+			return RoleAdmin
+		}
+	}
+
+	return u.users[username].role
+}
+
+// CanModerate reports whether the user who owns the actor character may act on the user who owns the target character.
+// Admins can act on anyone, everyone else only on users with a lower role than theirs.
+func (u *UsersManager) CanModerate(actorID, targetID model.CharacterID) bool {
+	role := u.Role(u.owner(actorID))
+	if role == RoleAdmin {
+		return true
+	}
+	return role > u.Role(u.owner(targetID))
+}
+
+// owner returns the username of the user who owns the character.
+func (u *UsersManager) owner(characterID model.CharacterID) string {
+	for username, user := range u.users {
+		for _, id := range user.characterIDs {
+			if id == characterID {
+				return username
+			}
+		}
+	}
+	return ""
+}
+
+// SetRole changes the user's role.
+func (u *UsersManager) SetRole(username string, role Role) error {
+	user, ok := u.users[username]
+	if !ok {
+		return errors.New("cannot find user")
+	}
+
+	user.role = role
+	u.users[username] = user
+
+	return nil
+}
+
+// SetAdmins sets the users that are always admins, whatever role they have been given.
+// Only accounts that already exist are made admins, otherwise the first person to register the name would be one.
+// The usernames that have no account are returned.
+func (u *UsersManager) SetAdmins(usernames []string) []string {
+	var unknown []string
+	u.admins = nil
+	for _, username := range usernames {
+		if _, ok := u.users[username]; !ok {
+			unknown = append(unknown, username)
+			continue
+		}
+		u.admins = append(u.admins, username)
+	}
+	return unknown
 }
 
 func (u *UsersManager) Register(username, password string) error {
@@ -65,33 +143,79 @@ func (u *UsersManager) AssociateCharacter(username string, characterID model.Cha
 		return errors.New("cannot find user")
 	}
 
-	user.characterID = characterID
+	user.characterIDs = append(user.characterIDs, characterID)
 
 	u.users[username] = user
 
 	return nil
 }
 
+// DisassociateCharacter removes the character from the user's list of characters.
+func (u *UsersManager) DisassociateCharacter(username string, characterID model.CharacterID) error {
+	user, ok := u.users[username]
+	if !ok {
+		return errors.New("cannot find user")
+	}
+
+	for i, id := range user.characterIDs {
+		if id == characterID {
+			user.characterIDs = append(user.characterIDs[:i:i], user.characterIDs[i+1:]...)
+			u.users[username] = user
+			return nil
+		}
+	}
+
+	return errors.New("user does not own character")
+}
+
 func (u *UsersManager) Save(p state.Persister) error {
 	// TODO: Save only things that need saving
 	for _, u := range u.users {
+		var characterIDs []string
+		for _, id := range u.characterIDs {
+			characterIDs = append(characterIDs, string(id))
+		}
+
 		p.QueueUser(state.User{
-			Username:    u.username,
-			Password:    u.password,
-			CharacterID: string(u.characterID),
+			Username:     u.username,
+			Password:     u.password,
+			CharacterIDs: characterIDs,
+			Role:         u.role.String(),
 		})
 	}
 
 	return nil
 }
 
+// Load adds the saved users. If any of them can't be read, none of them are added.
 func (u *UsersManager) Load(users []state.User) error {
+	loaded := make(map[string]User)
 	for _, user := range users {
-		u.users[user.Username] = User{
-			username:    user.Username,
-			password:    user.Password,
-			characterID: model.CharacterID(user.CharacterID),
+		var characterIDs []model.CharacterID
+		for _, id := range user.CharacterIDs {
+			characterIDs = append(characterIDs, model.CharacterID(id))
+		}
+
+		// users saved before accounts could have several characters only have the one
+		if user.CharacterID != "" && len(characterIDs) == 0 {
+			characterIDs = append(characterIDs, model.CharacterID(user.CharacterID))
 		}
+
+		role, err := ParseRole(user.Role)
+		if err != nil {
+			return fmt.Errorf("user %s: %w", user.Username, err)
+		}
+
+		loaded[user.Username] = User{
+			username:     user.Username,
+			password:     user.Password,
+			characterIDs: characterIDs,
+			role:         role,
+		}
+	}
+
+	for username, user := range loaded {
+		u.users[username] = user
 	}
 	return nil
 }
@@ -2,10 +2,25 @@ package simulation
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/soupstoregames/coda-mud/simulation/model"
 	"github.com/soupstoregames/go-core/logging"
 )
 
+// Moderators decides which characters staff can act on, by the roles of the users who own them.
+type Moderators interface {
+	// CanModerate reports whether the user who owns the actor outranks the user who owns the target
+	CanModerate(actorID, targetID model.CharacterID) bool
+}
+
+// SetModerators lets the simulation check roles before staff act on other characters.
+// Without it, staff can act on anyone.
+func (s *Simulation) SetModerators(moderators Moderators) {
+	s.moderators = moderators
+}
+
 func (s *Simulation) AdminSpawnItem(characterID model.CharacterID, id model.ItemDefinitionID) error {
 	actor, err := s.findAwakeCharacter(characterID)
 	if err != nil {
@@ -28,3 +43,232 @@ func (s *Simulation) AdminSpawnItem(characterID model.CharacterID, id model.Item
 
 	return nil
 }
+
+// adminGoto moves the admin to another character, or to a room if no character was named.
+// Rooms without a world are in the admin's current world.
+func (s *Simulation) adminGoto(actor *model.Character, c model.CommandAdminGoto) {
+	var room *model.Room
+	if c.Target != "" {
+		target := s.findAwakeCharacterByName(c.Target)
+		if target == nil {
+			actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+			return
+		}
+		room = target.Room
+	} else {
+		worldID := c.WorldID
+		if worldID == "" {
+			worldID = actor.Room.WorldID
+		}
+
+		var ok bool
+		if room, ok = s.findRoom(worldID, c.RoomID); !ok {
+			actor.Dispatch(model.EvtAdminRoomNotFound{WorldID: c.WorldID, RoomID: c.RoomID})
+			return
+		}
+	}
+
+	s.teleport(actor, room)
+}
+
+func (s *Simulation) adminSummon(actor *model.Character, c model.CommandAdminSummon) {
+	target := s.findAwakeCharacterByName(c.Target)
+	if target == nil || target == actor {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	s.teleport(target, actor.Room)
+
+	evt := model.EvtAdminTeleports{Admin: actor, Character: target, Room: actor.Room}
+	actor.Dispatch(evt)
+	target.Dispatch(evt)
+}
+
+func (s *Simulation) adminTeleport(actor *model.Character, c model.CommandAdminTeleport) {
+	target := s.findAwakeCharacterByName(c.Target)
+	if target == nil {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	room, ok := s.findRoom(c.WorldID, c.RoomID)
+	if !ok {
+		actor.Dispatch(model.EvtAdminRoomNotFound{WorldID: c.WorldID, RoomID: c.RoomID})
+		return
+	}
+
+	s.teleport(target, room)
+
+	evt := model.EvtAdminTeleports{Admin: actor, Character: target, Room: room}
+	actor.Dispatch(evt)
+	if target != actor {
+		target.Dispatch(evt)
+	}
+}
+
+// teleport moves the character straight to the room without using an exit.
+// Everyone in both rooms sees them go and arrive, but followers are left behind.
+func (s *Simulation) teleport(character *model.Character, room *model.Room) {
+	originalRoom := character.Room
+	originalRoom.RemoveCharacter(character)
+
+	if !originalRoom.Alone {
+		originalRoom.Dispatch(model.EvtCharacterLeaves{Character: character, Teleported: true})
+	}
+
+	character.Room = room
+	room.AddCharacter(character)
+	character.Dispatch(model.EvtRoomDescription{Room: room})
+
+	if !room.Alone {
+		arrival := model.EvtCharacterArrives{Character: character, Teleported: true}
+		for _, ch := range room.Characters {
+			if ch != character {
+				ch.Dispatch(arrival)
+			}
+		}
+	}
+
+	room.OnEnter(character)
+}
+
+// adminKick closes the character's connection and puts them to sleep straight away, without a linkdead grace period.
+func (s *Simulation) adminKick(actor *model.Character, c model.CommandAdminKick) {
+	target := s.findAwakeCharacterByName(c.Target)
+	if target == nil {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	if target != actor && s.moderators != nil && !s.moderators.CanModerate(actor.ID, target.ID) {
+		actor.Dispatch(model.EvtAdminCannotKick{Character: target})
+		return
+	}
+
+	evt := model.EvtAdminKicks{Admin: actor, Character: target}
+	if target != actor {
+		actor.Dispatch(evt)
+	}
+	target.Dispatch(evt)
+
+	s.sleep(target)
+	logging.Info(fmt.Sprintf("%s kicked %s", actor.Name, target.Name))
+}
+
+// adminInspect looks for the target as the room, then a character anywhere, then an item the admin can see.
+func (s *Simulation) adminInspect(actor *model.Character, c model.CommandAdminInspect) {
+	target := strings.ToLower(c.Target)
+	if target == "" || target == "here" || target == "room" {
+		actor.Dispatch(model.EvtAdminInspectRoom{Room: actor.Room, World: s.worlds[actor.Room.WorldID]})
+		return
+	}
+
+	if character := s.findCharacterByName(target); character != nil {
+		actor.Dispatch(model.EvtAdminInspectCharacter{Character: character})
+		return
+	}
+
+	item := actor.Room.FindItem(target)
+	if item == nil {
+		item = actor.SearchInventory(target)
+	}
+	if item == nil {
+		item = actor.Rig.FindItem(target)
+	}
+	if item != nil {
+		actor.Dispatch(model.EvtAdminInspectItem{Item: item})
+		return
+	}
+
+	actor.Dispatch(model.EvtAdminTargetNotFound{Target: c.Target})
+}
+
+// adminSet changes one of the character's fields. The field names are lower case.
+func (s *Simulation) adminSet(actor *model.Character, c model.CommandAdminSet) {
+	target := s.findCharacterByName(c.Target)
+	if target == nil {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	field := strings.ToLower(c.Field)
+	switch field {
+	case "name":
+		if strings.EqualFold(c.Value, target.Name) {
+			target.Name = c.Value
+			break
+		}
+		if err := s.validateCharacterName(c.Value); err != nil {
+			actor.Dispatch(model.EvtAdminSetFailed{Field: field, Reason: err.Error()})
+			return
+		}
+		target.Name = c.Value
+	case "description":
+		target.Description = c.Value
+	case "money":
+		money, err := strconv.ParseInt(c.Value, 10, 64)
+		if err != nil || money < 0 {
+			actor.Dispatch(model.EvtAdminSetFailed{Field: field, Reason: "money must be a whole number that isn't negative"})
+			return
+		}
+		target.Money = money
+	case "strength", "dexterity", "constitution", "intelligence":
+		value, err := strconv.Atoi(c.Value)
+		if err != nil || value < 0 {
+			actor.Dispatch(model.EvtAdminSetFailed{Field: field, Reason: "attributes must be whole numbers that aren't negative"})
+			return
+		}
+		switch field {
+		case "strength":
+			target.Attributes.Strength = value
+		case "dexterity":
+			target.Attributes.Dexterity = value
+		case "constitution":
+			target.Attributes.Constitution = value
+		case "intelligence":
+			target.Attributes.Intelligence = value
+		}
+	default:
+		actor.Dispatch(model.EvtAdminSetFailed{Field: field, Reason: "there is no such field"})
+		return
+	}
+
+	evt := model.EvtAdminSetsField{Admin: actor, Character: target, Field: field, Value: c.Value}
+	actor.Dispatch(evt)
+	if target != actor {
+		target.Dispatch(evt)
+	}
+}
+
+// adminPurge destroys everything on the floor of the admin's room.
+func (s *Simulation) adminPurge(actor *model.Character, c model.CommandAdminPurge) {
+	count := 0
+	for id := range actor.Room.Container.Items() {
+		actor.Room.Container.RemoveItem(id)
+		delete(s.items, id)
+		count++
+	}
+
+	s.dispatchToRoom(actor, model.EvtAdminPurgesRoom{Character: actor, Count: count})
+}
+
+func (s *Simulation) findRoom(worldID model.WorldID, roomID model.RoomID) (*model.Room, bool) {
+	world, ok := s.worlds[worldID]
+	if !ok {
+		return nil, false
+	}
+
+	room, ok := world.Rooms[roomID]
+	return room, ok
+}
+
+// findCharacterByName finds a character whether they are awake or not.
+func (s *Simulation) findCharacterByName(name string) *model.Character {
+	for _, ch := range s.characters {
+		if strings.EqualFold(ch.Name, name) {
+			return ch
+		}
+	}
+	return nil
+}
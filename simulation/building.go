@@ -0,0 +1,197 @@
+package simulation
+
+import (
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// DataWriter saves changes that builders make in the game back to the data folder,
+// then applies the saved data to the simulation the same way it would be loaded.
+type DataWriter interface {
+	DigRoom(worldID model.WorldID, fromRoomID model.RoomID, direction model.Direction, name string) (model.RoomID, error)
+	RenameRoom(worldID model.WorldID, roomID model.RoomID, name string) error
+	DescribeRoom(worldID model.WorldID, roomID model.RoomID, description string) error
+	SetRoomRegion(worldID model.WorldID, roomID model.RoomID, region string) error
+	LinkExit(worldID model.WorldID, roomID model.RoomID, direction model.Direction, toWorldID model.WorldID, toRoomID model.RoomID) error
+	UnlinkExit(worldID model.WorldID, roomID model.RoomID, direction model.Direction) error
+	CreateItemDefinition(name string) (model.ItemDefinitionID, error)
+	EditItemDefinition(id model.ItemDefinitionID, field, value string) error
+	// Reload loads any changes made to the data folder outside of the game
+	Reload() error
+}
+
+// SetDataWriter lets builders change the world from inside the game.
+func (s *Simulation) SetDataWriter(dataWriter DataWriter) {
+	s.dataWriter = dataWriter
+}
+
+// buildableRoom returns the actor's room if the data writer can save changes to it.
+// Rooms in instances are copies, so they can't be built in.
+func (s *Simulation) buildableRoom(actor *model.Character) (*model.Room, bool) {
+	if s.dataWriter == nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: "building is not enabled"})
+		return nil, false
+	}
+
+	if world, ok := s.worlds[actor.Room.WorldID]; !ok || world.Instance {
+		actor.Dispatch(model.EvtBuildFailed{Reason: "you can't build in an instance"})
+		return nil, false
+	}
+
+	return actor.Room, true
+}
+
+func (s *Simulation) dig(actor *model.Character, c model.CommandBuildDig) {
+	room, ok := s.buildableRoom(actor)
+	if !ok {
+		return
+	}
+
+	if room.Exits[c.Direction] != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: "there is already an exit " + c.Direction.String()})
+		return
+	}
+
+	roomID, err := s.dataWriter.DigRoom(room.WorldID, room.ID, c.Direction, c.Name)
+	if err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	newRoom, _ := s.findRoom(room.WorldID, roomID)
+	s.dispatchToRoom(actor, model.EvtBuilderDigs{Character: actor, Direction: c.Direction, Room: newRoom})
+}
+
+func (s *Simulation) renameRoom(actor *model.Character, c model.CommandBuildRename) {
+	room, ok := s.buildableRoom(actor)
+	if !ok {
+		return
+	}
+
+	if err := s.dataWriter.RenameRoom(room.WorldID, room.ID, c.Name); err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtRoomEdited{Room: room, Field: "name"})
+}
+
+func (s *Simulation) describeRoom(actor *model.Character, c model.CommandBuildDescribe) {
+	room, ok := s.buildableRoom(actor)
+	if !ok {
+		return
+	}
+
+	if err := s.dataWriter.DescribeRoom(room.WorldID, room.ID, c.Description); err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtRoomEdited{Room: room, Field: "description"})
+}
+
+func (s *Simulation) setRoomRegion(actor *model.Character, c model.CommandBuildRegion) {
+	room, ok := s.buildableRoom(actor)
+	if !ok {
+		return
+	}
+
+	if err := s.dataWriter.SetRoomRegion(room.WorldID, room.ID, c.Region); err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtRoomEdited{Room: room, Field: "region"})
+}
+
+// linkExit adds an exit from the actor's room. An empty WorldID means the room's own world.
+func (s *Simulation) linkExit(actor *model.Character, c model.CommandBuildExitLink) {
+	room, ok := s.buildableRoom(actor)
+	if !ok {
+		return
+	}
+
+	worldID := c.WorldID
+	if worldID == "" {
+		worldID = room.WorldID
+	}
+
+	if _, ok := s.findRoom(worldID, c.RoomID); !ok {
+		actor.Dispatch(model.EvtAdminRoomNotFound{WorldID: worldID, RoomID: c.RoomID})
+		return
+	}
+
+	if err := s.dataWriter.LinkExit(room.WorldID, room.ID, c.Direction, worldID, c.RoomID); err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtExitLinked{Direction: c.Direction, WorldID: worldID, RoomID: c.RoomID})
+}
+
+func (s *Simulation) unlinkExit(actor *model.Character, c model.CommandBuildExitUnlink) {
+	room, ok := s.buildableRoom(actor)
+	if !ok {
+		return
+	}
+
+	if room.Exits[c.Direction] == nil {
+		actor.Dispatch(model.EvtNoExitInThatDirection{})
+		return
+	}
+
+	if err := s.dataWriter.UnlinkExit(room.WorldID, room.ID, c.Direction); err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtExitUnlinked{Direction: c.Direction})
+}
+
+func (s *Simulation) createItemDefinition(actor *model.Character, c model.CommandBuildItemDefinitionCreate) {
+	if s.dataWriter == nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: "building is not enabled"})
+		return
+	}
+
+	id, err := s.dataWriter.CreateItemDefinition(c.Name)
+	if err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtItemDefinitionCreated{Definition: s.itemDefinitions[id]})
+}
+
+func (s *Simulation) editItemDefinition(actor *model.Character, c model.CommandBuildItemDefinitionEdit) {
+	if s.dataWriter == nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: "building is not enabled"})
+		return
+	}
+
+	if _, ok := s.itemDefinitions[c.ID]; !ok {
+		actor.Dispatch(model.EvtBuildFailed{Reason: ErrItemDefinitionNotFound.Error()})
+		return
+	}
+
+	if err := s.dataWriter.EditItemDefinition(c.ID, c.Field, c.Value); err != nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtItemDefinitionEdited{Definition: s.itemDefinitions[c.ID], Field: c.Field})
+}
+
+// reloadData loads changes to the data folder straight away, instead of waiting for the data watcher to notice them.
+func (s *Simulation) reloadData(actor *model.Character, c model.CommandBuildReload) {
+	if s.dataWriter == nil {
+		actor.Dispatch(model.EvtBuildFailed{Reason: "building is not enabled"})
+		return
+	}
+
+	if err := s.dataWriter.Reload(); err != nil {
+		actor.Dispatch(model.EvtDataReloadFailed{Reason: err.Error()})
+		return
+	}
+
+	actor.Dispatch(model.EvtDataReloaded{})
+}
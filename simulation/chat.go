@@ -0,0 +1,143 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+func (s *Simulation) tell(actor *model.Character, c model.CommandTell) {
+	target := s.findAwakeCharacterByName(c.Target)
+	if target == nil || target == actor {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	s.sendTell(actor, target, c.Content)
+}
+
+func (s *Simulation) reply(actor *model.Character, c model.CommandReply) {
+	target := actor.ReplyTo
+	if target == nil || !target.Awake {
+		actor.Dispatch(model.EvtNobodyToReplyTo{})
+		return
+	}
+
+	s.sendTell(actor, target, c.Content)
+}
+
+func (s *Simulation) sendTell(actor, target *model.Character, content string) {
+	target.ReplyTo = actor
+
+	evt := model.EvtTell{Character: actor, Target: target, Content: content}
+	actor.Dispatch(evt)
+	target.Dispatch(evt)
+}
+
+func (s *Simulation) whisper(actor *model.Character, c model.CommandWhisper) {
+	target := actor.Room.FindCharacter(c.Target)
+	if target == nil || target == actor || !target.Awake || actor.Room.Alone {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	evt := model.EvtWhisper{Character: actor, Target: target, Content: c.Content}
+	actor.Dispatch(evt)
+	target.Dispatch(evt)
+}
+
+// shout reaches every room in the actor's world that shares the region of the actor's room.
+func (s *Simulation) shout(actor *model.Character, c model.CommandShout) {
+	evt := model.EvtShout{Character: actor, Content: c.Content}
+
+	if actor.Room.Alone {
+		actor.Dispatch(evt)
+		return
+	}
+
+	for _, ch := range s.characters {
+		if !ch.Awake || ch.Room.WorldID != actor.Room.WorldID || ch.Room.Region != actor.Room.Region {
+			continue
+		}
+		ch.Dispatch(evt)
+	}
+}
+
+func (s *Simulation) emote(actor *model.Character, c model.CommandEmote) {
+	s.dispatchToRoom(actor, model.EvtEmote{Character: actor, Content: c.Content})
+}
+
+// social performs a social, aimed at a character in the room if the command has a target.
+// Aiming a social that can't be aimed at anyone performs it untargeted.
+func (s *Simulation) social(actor *model.Character, c model.CommandSocial) {
+	var target *model.Character
+	if c.Target != "" && c.Social.Targetable() {
+		if c.Target == "me" || c.Target == "self" {
+			target = actor
+		} else {
+			target = actor.Room.FindCharacter(c.Target)
+		}
+
+		if target == nil || (target != actor && actor.Room.Alone) {
+			actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+			return
+		}
+	}
+
+	s.dispatchToRoom(actor, model.EvtSocial{Social: c.Social, Character: actor, Target: target})
+}
+
+// joinChannel adds the channel to the character's list and sends them the channel's recent history.
+// Channels are created the first time anyone joins them.
+func (s *Simulation) joinChannel(actor *model.Character, c model.CommandChannelJoin) {
+	if actor.InChannel(c.Channel) {
+		actor.Dispatch(model.EvtAlreadyInChannel{Channel: c.Channel})
+		return
+	}
+
+	channel := s.findChannel(c.Channel)
+	actor.Channels = append(actor.Channels, c.Channel)
+
+	history := make([]model.ChannelMessage, len(channel.History))
+	copy(history, channel.History)
+
+	actor.Dispatch(model.EvtChannelJoined{Channel: channel, History: history})
+}
+
+func (s *Simulation) leaveChannel(actor *model.Character, c model.CommandChannelLeave) {
+	for i, name := range actor.Channels {
+		if name == c.Channel {
+			actor.Channels = append(actor.Channels[:i], actor.Channels[i+1:]...)
+			actor.Dispatch(model.EvtChannelLeft{Channel: c.Channel})
+			return
+		}
+	}
+
+	actor.Dispatch(model.EvtNotInChannel{Channel: c.Channel})
+}
+
+func (s *Simulation) channelSay(actor *model.Character, c model.CommandChannelSay) {
+	if !actor.InChannel(c.Channel) {
+		actor.Dispatch(model.EvtNotInChannel{Channel: c.Channel})
+		return
+	}
+
+	channel := s.findChannel(c.Channel)
+	channel.Record(model.ChannelMessage{Character: actor, Content: c.Content, Time: time.Now()})
+
+	evt := model.EvtChannelMessage{Channel: channel, Character: actor, Content: c.Content}
+	for _, ch := range s.characters {
+		if ch.Awake && ch.InChannel(c.Channel) {
+			ch.Dispatch(evt)
+		}
+	}
+}
+
+func (s *Simulation) findChannel(name string) *model.Channel {
+	channel, ok := s.channels[name]
+	if !ok {
+		channel = model.NewChannel(name)
+		s.channels[name] = channel
+	}
+	return channel
+}
@@ -46,13 +46,8 @@ func (p *FileSystem) Persist() error {
 	}
 
 	for i := range p.users {
-		f, err := os.Create(filepath.Join(p.rootFolder, "users", p.users[i].Username+".toml"))
-		if err != nil {
-			return errors.Wrap(err, "Error opening file to write")
-		}
-
-		if err := toml.NewEncoder(f).Encode(p.users[i]); err != nil {
-			return errors.Wrap(err, "Error encoding TOML: %s")
+		if err := writeTOML(filepath.Join(p.rootFolder, "users", p.users[i].Username+".toml"), p.users[i]); err != nil {
+			return err
 		}
 	}
 
@@ -66,13 +61,8 @@ func (p *FileSystem) Persist() error {
 	}
 
 	for i := range p.characters {
-		f, err := os.Create(filepath.Join(p.rootFolder, "characters", p.characters[i].ID+".toml"))
-		if err != nil {
-			return errors.Wrap(err, "Error opening file to write")
-		}
-
-		if err := toml.NewEncoder(f).Encode(p.characters[i]); err != nil {
-			return errors.Wrap(err, "Error encoding TOML: %s")
+		if err := writeTOML(filepath.Join(p.rootFolder, "characters", p.characters[i].ID+".toml"), p.characters[i]); err != nil {
+			return err
 		}
 	}
 
@@ -86,13 +76,8 @@ func (p *FileSystem) Persist() error {
 	}
 
 	for i := range p.worlds {
-		f, err := os.Create(filepath.Join(p.rootFolder, "worlds", p.worlds[i].ID+".toml"))
-		if err != nil {
-			return errors.Wrap(err, "Error opening file to write")
-		}
-
-		if err := toml.NewEncoder(f).Encode(p.worlds[i]); err != nil {
-			return errors.Wrap(err, "Error encoding TOML: %s")
+		if err := writeTOML(filepath.Join(p.rootFolder, "worlds", p.worlds[i].ID+".toml"), p.worlds[i]); err != nil {
+			return err
 		}
 	}
 
@@ -104,6 +89,30 @@ func (p *FileSystem) Persist() error {
 	return nil
 }
 
+// writeTOML writes the file through a temporary file that replaces it once it is complete,
+// so that stopping part way through a save leaves the last save in place rather than half a file.
+func writeTOML(filePath string, v interface{}) error {
+	tmpPath := filePath + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Wrap(err, "Error opening file to write")
+	}
+
+	if err := toml.NewEncoder(f).Encode(v); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "Error encoding TOML")
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "Error writing file")
+	}
+
+	return errors.Wrap(os.Rename(tmpPath, filePath), "Error replacing file")
+}
+
 func (p *FileSystem) QueueUser(u User) {
 	p.users = append(p.users, u)
 }
@@ -116,6 +125,15 @@ func (p *FileSystem) QueueWorld(w World) {
 	p.worlds = append(p.worlds, w)
 }
 
+// DeleteCharacter removes the character's saved state. It is not an error if the character was never saved.
+func (p *FileSystem) DeleteCharacter(id string) error {
+	err := os.Remove(filepath.Join(p.rootFolder, "characters", id+".toml"))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "Error deleting character file")
+	}
+	return nil
+}
+
 func (p *FileSystem) Load() ([]User, []Character, []World, error) {
 	var (
 		folderPath string
@@ -135,6 +153,11 @@ func (p *FileSystem) Load() ([]User, []Character, []World, error) {
 		}
 
 		for _, fileInfo := range files {
+			// a .tmp file is a save that was stopped part way through
+			if filepath.Ext(fileInfo.Name()) != ".toml" {
+				continue
+			}
+
 			var u User
 			if _, err := toml.DecodeFile(filepath.Join(folderPath, fileInfo.Name()), &u); err != nil {
 				return nil, nil, nil, err
@@ -152,6 +175,10 @@ func (p *FileSystem) Load() ([]User, []Character, []World, error) {
 		}
 
 		for _, fileInfo := range files {
+			if filepath.Ext(fileInfo.Name()) != ".toml" {
+				continue
+			}
+
 			var c Character
 			if _, err := toml.DecodeFile(filepath.Join(folderPath, fileInfo.Name()), &c); err != nil {
 				return nil, nil, nil, err
@@ -172,6 +199,10 @@ func (p *FileSystem) Load() ([]User, []Character, []World, error) {
 		}
 
 		for _, fileInfo := range files {
+			if filepath.Ext(fileInfo.Name()) != ".toml" {
+				continue
+			}
+
 			var w World
 			if _, err := toml.DecodeFile(filepath.Join(folderPath, fileInfo.Name()), &w); err != nil {
 				return nil, nil, nil, err
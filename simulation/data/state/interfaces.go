@@ -6,6 +6,7 @@ type Persister interface {
 	QueueUser(User)
 	QueueCharacter(Character)
 	QueueWorld(World)
+	DeleteCharacter(id string) error
 }
 
 // Loader is an object that can load game state.
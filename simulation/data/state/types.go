@@ -1,9 +1,13 @@
 package state
 
 type User struct {
-	Username    string
-	Password    []byte
-	CharacterID string
+	Username     string
+	Password     []byte
+	CharacterIDs []string
+	// Role is the name of the user's role, players are saved without one
+	Role string `toml:",omitempty"`
+	// CharacterID is only read from users saved before accounts could have several characters
+	CharacterID string `toml:",omitempty"`
 }
 
 type Character struct {
@@ -13,16 +17,51 @@ type Character struct {
 	World string
 	Rig   Rig
 	Items []*Item
+	Money int64
+	// Quests holds the character's progress through every quest they have started
+	Quests []QuestProgress
+	Flags  []string
+	// Channels are the names of the chat channels the character has joined
+	Channels []string
+
+	Description string
+	Attributes  Attributes
+	Background  int64
+}
+
+type Attributes struct {
+	Strength     int
+	Dexterity    int
+	Constitution int
+	Intelligence int
+}
+
+type QuestProgress struct {
+	ID        int64
+	Step      int
+	Completed bool
 }
 
+// Rig holds each equipped item under its primary rig slot.
+// Items that occupy several slots are only stored once.
 type Rig struct {
 	Backpack *Item
+	Head     *Item
+	Torso    *Item
+	Legs     *Item
+	Feet     *Item
+	Hands    *Item
+	Belt     *Item
+	MainHand *Item
+	OffHand  *Item
 }
 
 type Item struct {
 	ID             string
 	ItemDefinition int64
-	Items          []*Item
+	// Quantity is the size of the stack, older saves leave it at zero which means one
+	Quantity int
+	Items    []*Item
 }
 
 type World struct {
@@ -33,4 +72,6 @@ type World struct {
 type Room struct {
 	ID    int64
 	Items []*Item
+	// Doors maps the direction of each exit with a door to the door's state
+	Doors map[string]string
 }
@@ -0,0 +1,55 @@
+package static
+
+type Background struct {
+	Name        string
+	Description string
+	// Money is how much money characters with the background start with
+	Money int64
+	// Kit is the items characters with the background start with
+	Kit []Component
+}
+
+// itemIDs are the items in the background's kit
+func (b *Background) itemIDs() []int {
+	var ids []int
+	for _, c := range b.Kit {
+		ids = append(ids, c.ItemID)
+	}
+	return ids
+}
+
+// loadAllBackgrounds loads every background in the backgrounds folder. The backgrounds folder is optional.
+func loadAllBackgrounds(backgroundsBaseFolder string) (map[int]*Background, error) {
+	backgrounds := make(map[int]*Background)
+
+	if !fileExists(backgroundsBaseFolder) {
+		return backgrounds, nil
+	}
+
+	_, err := loadDataFiles(backgroundsBaseFolder, func(id int, filePath string) error {
+		background, err := loadBackground(filePath)
+		if err != nil {
+			return err
+		}
+		backgrounds[id] = background
+		return nil
+	})
+	return backgrounds, err
+}
+
+// loadBackground reads the background file data and decodes it from whichever format it is in
+func loadBackground(filepath string) (*Background, error) {
+	var background Background
+	if err := decodeFile(filepath, &background); err != nil {
+		return nil, err
+	}
+
+	// a kit item without a quantity means one of them
+	for i := range background.Kit {
+		if background.Kit[i].Quantity == 0 {
+			background.Kit[i].Quantity = 1
+		}
+	}
+
+	return &background, nil
+}
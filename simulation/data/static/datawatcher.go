@@ -1,10 +1,10 @@
 package static
 
 import (
-	"errors"
 	"fmt"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/nicklanng/fsdiff"
@@ -13,13 +13,16 @@ import (
 	"github.com/soupstoregames/go-core/logging"
 )
 
-const roomExtension = ".toml"
-
 type DataWatcher struct {
 	Errors        chan error
 	dataFolder    string
 	lastDataState *fsdiff.Node
 	sim           simulation.WorldController
+
+	// worlds are the world files of the loaded worlds, by folder name
+	worlds map[string]*World
+	// templates are the room templates, by name
+	templates map[string]*Room
 }
 
 func NewDataWatcher(rootPath string, sim simulation.WorldController) *DataWatcher {
@@ -27,6 +30,8 @@ func NewDataWatcher(rootPath string, sim simulation.WorldController) *DataWatche
 		Errors:     make(chan error, 1),
 		dataFolder: rootPath,
 		sim:        sim,
+		worlds:     make(map[string]*World),
+		templates:  make(map[string]*Room),
 	}
 
 	return dw
@@ -41,256 +46,368 @@ func (dw *DataWatcher) InitialLoad() error {
 	return nil
 }
 
-func (dw *DataWatcher) Watch() {
-	// regularly load data folder and check for differences
-	t := time.NewTicker(time.Minute)
+// Poll checks the data folder for differences at the interval.
+// It is slower to notice changes than Watch, but works on file systems that don't send events.
+func (dw *DataWatcher) Poll(interval time.Duration) {
+	t := time.NewTicker(interval)
 	go func() {
 		for range t.C {
-			// get current state of data folder
-			newState, err := fsdiff.BuildTree(dw.dataFolder)
-			if err != nil {
+			if err := dw.Reload(); err != nil {
 				dw.Errors <- err
-				continue
 			}
-
-			// find the diffed files
-			diff := fsdiff.Compare(dw.lastDataState, newState)
-
-			// apply diffs to simulation
-			dw.applyDiff(diff)
-
-			// save state for next time
-			dw.lastDataState = newState
 		}
 	}()
 }
 
+// Reload compares the data folder with how it was when it was last loaded, and applies the differences to the simulation.
+// If any of the changed files have problems, none of the changes are applied and a ReloadError lists the problems.
+func (dw *DataWatcher) Reload() error {
+	var err error
+	dw.sim.Update(func() {
+		err = dw.reload()
+	})
+	return err
+}
+
 func (dw *DataWatcher) initialLoad() (*fsdiff.Node, error) {
 	items, err := loadAllItems(path.Join(dw.dataFolder, "items"))
 	if err != nil {
 		return nil, err
 	}
 
-	worlds, err := loadAllWorlds(path.Join(dw.dataFolder, "rooms"))
+	shops, err := loadAllShops(path.Join(dw.dataFolder, "shops"))
 	if err != nil {
 		return nil, err
 	}
 
-	state, err := fsdiff.BuildTree(dw.dataFolder)
+	recipes, err := loadAllRecipes(path.Join(dw.dataFolder, "recipes"))
 	if err != nil {
 		return nil, err
 	}
 
-	// load items
-	for fileID, item := range items {
-		itemDefinitionID := model.ItemDefinitionID(fileID)
-		dw.addItemToSim(itemDefinitionID, item)
+	quests, err := loadAllQuests(path.Join(dw.dataFolder, "quests"))
+	if err != nil {
+		return nil, err
 	}
 
-	// load worlds
-	for worldID, rooms := range worlds {
-		wID := model.WorldID(worldID)
-		dw.addWorldToSim(wID, rooms)
+	socials, err := loadAllSocials(path.Join(dw.dataFolder, "socials"))
+	if err != nil {
+		return nil, err
 	}
 
-	return state, nil
-}
-
-func (dw *DataWatcher) applyDiff(diff *fsdiff.Diff) {
-	if diff.DiffType == fsdiff.DiffTypeNone {
-		return
+	backgrounds, err := loadAllBackgrounds(path.Join(dw.dataFolder, "backgrounds"))
+	if err != nil {
+		return nil, err
 	}
 
-	// get room folder
-	rooms, ok := searchChildrenForName(diff, "rooms")
-	if !ok {
-		dw.Errors <- errors.New("no rooms folder")
-		return
+	templates, _, err := loadAllTemplates(path.Join(dw.dataFolder, "templates"))
+	if err != nil {
+		return nil, err
 	}
 
-	dw.applyWorldDiffs(rooms)
+	worlds, err := loadAllWorlds(path.Join(dw.dataFolder, "rooms"))
+	if err != nil {
+		return nil, err
+	}
 
-	return
-}
+	state, err := fsdiff.BuildTree(dw.dataFolder)
+	if err != nil {
+		return nil, err
+	}
 
-func (dw *DataWatcher) applyWorldDiffs(diff *fsdiff.Diff) {
-	// has room folder changed?
-	if diff.DiffType != fsdiff.DiffTypeChanged {
-		return
+	// load items
+	for fileID, item := range items {
+		itemDefinitionID := model.ItemDefinitionID(fileID)
+		if err := dw.addItemToSim(itemDefinitionID, item); err != nil {
+			return nil, fmt.Errorf("item %d: %s", fileID, err.Error())
+		}
 	}
 
-	for _, world := range diff.Children {
-		// if this world hasnt changed - move on
-		if world.DiffType == fsdiff.DiffTypeNone {
-			continue
+	// load shops
+	for fileID, shop := range shops {
+		if err := dw.addShopToSim(model.ShopID(fileID), shop); err != nil {
+			return nil, err
 		}
+	}
 
-		worldID := model.WorldID(filepath.Base(world.Path))
+	// load recipes
+	for fileID, recipe := range recipes {
+		if err := dw.addRecipeToSim(model.RecipeID(fileID), recipe); err != nil {
+			return nil, err
+		}
+	}
 
-		// the world has been added - load all rooms into the sim
-		if world.DiffType == fsdiff.DiffTypeAdded {
-			rooms, err := loadWorldFolder(world.Path)
-			if err != nil {
-				dw.Errors <- errors.New("failed to load world")
-			}
+	// load quests
+	for fileID, quest := range quests {
+		if err := dw.addQuestToSim(model.QuestID(fileID), quest); err != nil {
+			return nil, err
+		}
+	}
 
-			for roomID, room := range rooms {
-				rID := model.RoomID(roomID)
-				dw.addRoomToSim(worldID, rID, room)
-			}
+	// load socials
+	for name, social := range socials {
+		dw.addSocialToSim(name, social)
+	}
 
-			logging.Info(fmt.Sprintf("Loaded world '%s' with %d rooms", worldID, len(rooms)))
+	// load backgrounds
+	for fileID, background := range backgrounds {
+		if err := dw.addBackgroundToSim(model.BackgroundID(fileID), background); err != nil {
+			return nil, err
 		}
+	}
 
-		// the world has been added - load all rooms into the sim
-		if world.DiffType == fsdiff.DiffTypeRemoved {
-			dw.sim.DestroyWorld(worldID)
+	// load templates, before the rooms that inherit from them
+	dw.templates = templates
 
-			logging.Info(fmt.Sprintf("Removed world '%s'", worldID))
+	// load worlds
+	for worldID, rooms := range worlds {
+		world, err := loadWorld(path.Join(dw.dataFolder, "rooms", worldID))
+		if err != nil {
+			return nil, err
 		}
 
-		// the world have been changed, move down to the room level
-		if world.DiffType == fsdiff.DiffTypeChanged {
-			for _, room := range world.Children {
-				if filepath.Ext(room.Path) != roomExtension {
-					continue
-				}
-
-				switch room.DiffType {
-				case fsdiff.DiffTypeAdded:
-					roomID, err := getRoomID(filepath.Base(room.Path))
-					if err != nil {
-						dw.Errors <- err
-						continue
-					}
-
-					room, err := loadRoom(room.Path)
-					if err != nil {
-						dw.Errors <- err
-						continue
-					}
-
-					dw.addRoomToSim(worldID, model.RoomID(roomID), room)
-					logging.Info(fmt.Sprintf("Added room %d to world '%s'", roomID, worldID))
-
-				case fsdiff.DiffTypeRemoved:
-					roomID, err := getRoomID(filepath.Base(room.Path))
-					if err != nil {
-						dw.Errors <- err
-						continue
-					}
-
-					dw.sim.DestroyRoom(worldID, model.RoomID(roomID))
-					logging.Info(fmt.Sprintf("Removed room %d in world '%s'", roomID, worldID))
-
-				case fsdiff.DiffTypeChanged:
-					roomID, err := getRoomID(filepath.Base(room.Path))
-					if err != nil {
-						dw.Errors <- err
-						continue
-					}
-
-					room, err := loadRoom(room.Path)
-					if err != nil {
-						dw.Errors <- err
-						continue
-					}
-
-					dw.updateRoomInSim(worldID, model.RoomID(roomID), room)
-					logging.Info(fmt.Sprintf("Updated room %d in world '%s'", roomID, worldID))
-				}
-			}
+		wID := model.WorldID(worldID)
+		if err := dw.addWorldToSim(wID, world, rooms); err != nil {
+			return nil, fmt.Errorf("world '%s': %s", worldID, err.Error())
 		}
 	}
+
+	return state, nil
 }
 
-func (dw *DataWatcher) addWorldToSim(worldID model.WorldID, rooms map[int]*Room) {
-	var (
-		instancable bool
-		alone       bool
-	)
-	if worldID[0] == '!' {
-		instancable = true
-	}
-	if worldID[0] == '@' {
-		alone = true
-	}
+// SpawnRoom is where new characters begin, and where characters go when there is nowhere else for them.
+func (dw *DataWatcher) SpawnRoom() (model.WorldID, model.RoomID) {
+	worldID, roomID := startRoom(dw.worlds)
+	return model.WorldID(worldID), model.RoomID(roomID)
+}
+
+func (dw *DataWatcher) addWorldToSim(worldID model.WorldID, world *World, rooms map[int]*Room) error {
+	instancable := world.hasFlag(string(worldID), WorldFlagInstancable)
+	alone := world.hasFlag(string(worldID), WorldFlagAlone)
 	dw.sim.CreateWorld(worldID, instancable, alone)
 
+	if err := dw.updateWorldInSim(worldID, world); err != nil {
+		return err
+	}
+
 	// load rooms
 	for roomID, room := range rooms {
 		rID := model.RoomID(roomID)
 
-		dw.addRoomToSim(worldID, rID, room)
+		if err := dw.addRoomToSim(worldID, rID, room); err != nil {
+			return fmt.Errorf("room %d: %s", roomID, err.Error())
+		}
 	}
 
 	logging.Info(fmt.Sprintf("Loaded world '%s' with %d rooms", worldID, len(rooms)))
+	return nil
 }
 
-func (dw *DataWatcher) addRoomToSim(worldID model.WorldID, roomID model.RoomID, room *Room) error {
-	r, err := dw.sim.CreateRoom(worldID, roomID, room.Name, room.Region, room.Description, room.Script)
+// updateWorldInSim changes the details of a world that is already in the simulation.
+func (dw *DataWatcher) updateWorldInSim(worldID model.WorldID, world *World) error {
+	w, err := dw.sim.GetWorld(worldID)
 	if err != nil {
 		return err
 	}
 
-	// load room exits
-	for direction, exit := range room.Exits {
-		d, err := model.StringToDirection(direction)
-		if err != nil {
-			return err
-		}
+	if err := world.applyTo(w); err != nil {
+		return err
+	}
 
-		// if no worldID is provided, it defaults to the same as the room lives in
-		if exit.WorldID == "" {
-			exit.WorldID = string(worldID)
-		}
+	dw.worlds[string(worldID)] = world
+	return nil
+}
 
-		r.Exits[d] = &model.Exit{
-			WorldID: model.WorldID(exit.WorldID),
-			RoomID:  model.RoomID(exit.RoomID),
-		}
+// region is the room's region, or the world's if the room doesn't have one.
+func (dw *DataWatcher) region(worldID model.WorldID, room *Room) string {
+	if room.Region != "" {
+		return room.Region
+	}
+	if world, ok := dw.worlds[string(worldID)]; ok {
+		return world.Region
 	}
+	return ""
+}
 
-	return nil
+func (dw *DataWatcher) addRoomToSim(worldID model.WorldID, roomID model.RoomID, room *Room) error {
+	room, err := resolveRoom(room, dw.templates)
+	if err != nil {
+		return err
+	}
+
+	r, err := dw.sim.CreateRoom(worldID, roomID, room.Name, dw.region(worldID, room), room.Description, room.Script)
+	if err != nil {
+		return err
+	}
+
+	r.Extras = makeExtras(room.Extras)
+	r.ShopID = model.ShopID(room.Shop)
+	r.Tags = room.Tags
+
+	return loadRoomExits(worldID, r, room)
 }
 
 func (dw *DataWatcher) updateRoomInSim(worldID model.WorldID, roomID model.RoomID, room *Room) error {
+	room, err := resolveRoom(room, dw.templates)
+	if err != nil {
+		return err
+	}
+
 	r, err := dw.sim.GetRoom(worldID, roomID)
 	if err != nil {
 		return err
 	}
 
+	oldExits := r.Exits
+	oldNamedExits := r.NamedExits
+
 	r.Name = room.Name
+	r.Region = dw.region(worldID, room)
 	r.Description = room.Description
 	r.Exits = make(map[model.Direction]*model.Exit)
+	r.NamedExits = make(map[string]*model.Exit)
+	r.Extras = makeExtras(room.Extras)
+	r.ShopID = model.ShopID(room.Shop)
+	r.Tags = room.Tags
 	r.UpdateScript(room.Script)
 
-	// load room exits
+	if err := loadRoomExits(worldID, r, room); err != nil {
+		return err
+	}
+
+	// keep doors and revealed exits in the state players left them in
+	for d, exit := range r.Exits {
+		if old := oldExits[d]; old != nil && exit != nil {
+			keepExitState(old, exit)
+		}
+	}
+	for keyword, exit := range r.NamedExits {
+		if old := oldNamedExits[keyword]; old != nil {
+			keepExitState(old, exit)
+		}
+	}
+
+	return nil
+}
+
+// loadRoomExits converts the room's compass and named exits and adds them to the simulation room
+func loadRoomExits(worldID model.WorldID, r *model.Room, room *Room) error {
 	for direction, exit := range room.Exits {
 		d, err := model.StringToDirection(direction)
 		if err != nil {
 			return err
 		}
 
-		// if no worldID is provided, it defaults to the same as the room lives in
-		if exit.WorldID == "" {
-			exit.WorldID = string(worldID)
+		r.Exits[d], err = makeExit(worldID, exit)
+		if err != nil {
+			return err
+		}
+	}
+
+	for keyword, exit := range room.NamedExits {
+		if exit.Door != "" {
+			return fmt.Errorf("named exit '%s' cannot have a door, only compass exits can", keyword)
+		}
+
+		e, err := makeExit(worldID, exit)
+		if err != nil {
+			return err
 		}
 
-		r.Exits[d] = &model.Exit{
-			WorldID: model.WorldID(exit.WorldID),
-			RoomID:  model.RoomID(exit.RoomID),
+		e.Keyword = strings.ToLower(keyword)
+		if e.Name == "" {
+			e.Name = e.Keyword
 		}
+		r.NamedExits[e.Keyword] = e
 	}
 
 	return nil
 }
 
+// makeExit converts the static exit into a simulation exit
+func makeExit(worldID model.WorldID, exit Exit) (*model.Exit, error) {
+	// if no worldID is provided, it defaults to the same as the room lives in
+	if exit.WorldID == "" {
+		exit.WorldID = string(worldID)
+	}
+
+	e := &model.Exit{
+		WorldID:   model.WorldID(exit.WorldID),
+		RoomID:    model.RoomID(exit.RoomID),
+		Name:      exit.Name,
+		Hidden:    exit.Hidden,
+		Condition: exit.Condition,
+	}
+
+	if exit.Door != "" {
+		state, err := model.StringToDoorState(exit.Door)
+		if err != nil {
+			return nil, err
+		}
+		e.Door = model.NewDoor(state, model.ItemDefinitionID(exit.Key))
+	}
+
+	return e, nil
+}
+
+// makeExtras converts the static extra descriptions into simulation ones
+func makeExtras(extras []Extra) []*model.ExtraDescription {
+	var result []*model.ExtraDescription
+	for _, extra := range extras {
+		result = append(result, &model.ExtraDescription{
+			Keywords:    extra.Keywords,
+			Description: extra.Description,
+		})
+	}
+	return result
+}
+
+// keepExitState copies the parts of an exit that players can change on to its reloaded replacement
+func keepExitState(old, exit *model.Exit) {
+	if old.Door != nil && exit.Door != nil {
+		exit.Door.State = old.Door.State
+	}
+	if !old.Hidden {
+		exit.Hidden = false
+	}
+}
+
 func (dw *DataWatcher) addItemToSim(itemDefinitionID model.ItemDefinitionID, item *Item) error {
-	var rigSlot model.RigSlot
-	switch item.RigSlot {
-	case "backpack":
-		rigSlot = model.RigSlotBackpack
+	rigSlot, extraRigSlots, err := item.rigSlots()
+	if err != nil {
+		return err
+	}
+
+	var container *model.ContainerDefinition
+	if item.Container != nil {
+		container = &model.ContainerDefinition{}
+	}
+
+	definition, err := dw.sim.CreateItemDefinition(itemDefinitionID, item.Name, item.Aliases, item.Weight, rigSlot, extraRigSlots, container)
+	if err != nil {
+		return err
+	}
+
+	definition.ShortDescription = item.ShortDescription
+	definition.LongDescription = item.LongDescription
+	definition.Stackable = item.Stackable
+	definition.PluralName = item.Plural
+	definition.Value = item.Value
+
+	return nil
+}
+
+// updateItemInSim changes an item definition that is already in the simulation,
+// so items that have already been spawned from it change too.
+func (dw *DataWatcher) updateItemInSim(itemDefinitionID model.ItemDefinitionID, item *Item) error {
+	definition, err := dw.sim.GetItemDefinition(itemDefinitionID)
+	if err != nil {
+		return dw.addItemToSim(itemDefinitionID, item)
+	}
+
+	rigSlot, extraRigSlots, err := item.rigSlots()
+	if err != nil {
+		return err
 	}
 
 	var container *model.ContainerDefinition
@@ -298,7 +415,82 @@ func (dw *DataWatcher) addItemToSim(itemDefinitionID model.ItemDefinitionID, ite
 		container = &model.ContainerDefinition{}
 	}
 
-	_, err := dw.sim.CreateItemDefinition(itemDefinitionID, item.Name, item.Aliases, item.Weight, rigSlot, container)
+	definition.Name = item.Name
+	definition.Aliases = append(item.Aliases, item.Name)
+	definition.Weight = item.Weight
+	definition.RigSlot = rigSlot
+	definition.ExtraRigSlots = extraRigSlots
+	definition.Container = container
+	definition.ShortDescription = item.ShortDescription
+	definition.LongDescription = item.LongDescription
+	definition.Stackable = item.Stackable
+	definition.PluralName = item.Plural
+	definition.Value = item.Value
+
+	return nil
+}
+
+func (dw *DataWatcher) addRecipeToSim(recipeID model.RecipeID, recipe *Recipe) error {
+	inputs := make(map[model.ItemDefinitionID]int)
+	for _, c := range recipe.Inputs {
+		inputs[model.ItemDefinitionID(c.ItemID)] += c.Quantity
+	}
+
+	var tools []model.ItemDefinitionID
+	for _, id := range recipe.Tools {
+		tools = append(tools, model.ItemDefinitionID(id))
+	}
+
+	outputs := make(map[model.ItemDefinitionID]int)
+	for _, c := range recipe.Outputs {
+		outputs[model.ItemDefinitionID(c.ItemID)] += c.Quantity
+	}
+
+	_, err := dw.sim.CreateRecipe(recipeID, recipe.Name, recipe.Aliases, recipe.RoomTag, inputs, tools, outputs)
+	return err
+}
+
+func (dw *DataWatcher) addQuestToSim(questID model.QuestID, quest *Quest) error {
+	steps, err := quest.steps()
+	if err != nil {
+		return err
+	}
+
+	rewardItems := make(map[model.ItemDefinitionID]int)
+	for _, c := range quest.Rewards.Items {
+		rewardItems[model.ItemDefinitionID(c.ItemID)] += c.Quantity
+	}
+
+	_, err = dw.sim.CreateQuest(questID, quest.Name, quest.Description, steps, quest.Rewards.Money, rewardItems)
+	return err
+}
+
+func (dw *DataWatcher) addSocialToSim(name string, social *Social) {
+	dw.sim.CreateSocial(name, social.Untargeted.toModel(), social.Targeted.toModel(), social.Self.toModel())
+}
+
+func (dw *DataWatcher) addBackgroundToSim(backgroundID model.BackgroundID, background *Background) error {
+	kit := make(map[model.ItemDefinitionID]int)
+	for _, c := range background.Kit {
+		kit[model.ItemDefinitionID(c.ItemID)] += c.Quantity
+	}
+
+	_, err := dw.sim.CreateBackground(backgroundID, background.Name, background.Description, background.Money, kit)
+	return err
+}
+
+func (dw *DataWatcher) addShopToSim(shopID model.ShopID, shop *Shop) error {
+	restockInterval, err := shop.restockInterval()
+	if err != nil {
+		return err
+	}
+
+	stock := make(map[model.ItemDefinitionID]int)
+	for _, s := range shop.Stock {
+		stock[model.ItemDefinitionID(s.ItemID)] += s.Quantity
+	}
+
+	_, err = dw.sim.CreateShop(shopID, shop.Name, shop.Keeper, shop.BuyMultiplier, shop.SellMultiplier, restockInterval, stock)
 	return err
 }
 
@@ -0,0 +1,371 @@
+package static
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// DataWriter saves changes made by builders in the game to the data folder,
+// in the same format the DataWatcher loads, and then applies the saved files to the simulation.
+// The DataWatcher will see the changed files later, but loading them again changes nothing.
+type DataWriter struct {
+	dw *DataWatcher
+}
+
+func NewDataWriter(dw *DataWatcher) *DataWriter {
+	return &DataWriter{dw: dw}
+}
+
+// DigRoom makes a new room in the world, linked to the existing room in both directions.
+// The new room takes the region of the room it was dug from.
+func (w *DataWriter) DigRoom(worldID model.WorldID, fromRoomID model.RoomID, direction model.Direction, name string) (model.RoomID, error) {
+	if err := checkName(name); err != nil {
+		return 0, err
+	}
+
+	worldFolder := w.worldFolder(worldID)
+
+	fromPath, from, err := w.readRoom(worldID, fromRoomID)
+	if err != nil {
+		return 0, err
+	}
+
+	roomID, err := nextID(worldFolder)
+	if err != nil {
+		return 0, err
+	}
+
+	// the region may come from the room's template
+	resolved, err := resolveRoom(from, w.dw.templates)
+	if err != nil {
+		return 0, err
+	}
+
+	room := &Room{
+		Name:   name,
+		Region: resolved.Region,
+		Exits: map[string]Exit{
+			direction.Opposite().String(): {RoomID: int(fromRoomID)},
+		},
+	}
+	// the new room is saved in the same format as the room it was dug from
+	if err := writeDataFile(path.Join(worldFolder, fileName(roomID, name, filepath.Ext(fromPath))), room); err != nil {
+		return 0, err
+	}
+
+	if from.Exits == nil {
+		from.Exits = make(map[string]Exit)
+	}
+	from.Exits[direction.String()] = Exit{RoomID: roomID}
+	if err := writeDataFile(fromPath, from); err != nil {
+		return 0, err
+	}
+
+	if err := w.dw.addRoomToSim(worldID, model.RoomID(roomID), room); err != nil {
+		return 0, err
+	}
+	if err := w.dw.updateRoomInSim(worldID, fromRoomID, from); err != nil {
+		return 0, err
+	}
+
+	return model.RoomID(roomID), nil
+}
+
+// RenameRoom changes the room's name, and renames its files to match.
+func (w *DataWriter) RenameRoom(worldID model.WorldID, roomID model.RoomID, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+
+	roomPath, room, err := w.readRoom(worldID, roomID)
+	if err != nil {
+		return err
+	}
+
+	room.Name = name
+
+	newPath := path.Join(w.worldFolder(worldID), fileName(int(roomID), name, filepath.Ext(roomPath)))
+	if err := writeDataFile(newPath, room); err != nil {
+		return err
+	}
+
+	if newPath != roomPath {
+		if err := os.Remove(roomPath); err != nil {
+			return err
+		}
+
+		// the room's script is named after the room too
+		luaPath := scriptPath(roomPath)
+		if fileExists(luaPath) {
+			if err := os.Rename(luaPath, scriptPath(newPath)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.dw.updateRoomInSim(worldID, roomID, room)
+}
+
+func (w *DataWriter) DescribeRoom(worldID model.WorldID, roomID model.RoomID, description string) error {
+	return w.editRoom(worldID, roomID, func(room *Room) error {
+		room.Description = description
+		return nil
+	})
+}
+
+func (w *DataWriter) SetRoomRegion(worldID model.WorldID, roomID model.RoomID, region string) error {
+	return w.editRoom(worldID, roomID, func(room *Room) error {
+		room.Region = region
+		return nil
+	})
+}
+
+// LinkExit adds or replaces the compass exit. Exits within the same world are saved without a world ID.
+func (w *DataWriter) LinkExit(worldID model.WorldID, roomID model.RoomID, direction model.Direction, toWorldID model.WorldID, toRoomID model.RoomID) error {
+	return w.editRoom(worldID, roomID, func(room *Room) error {
+		exit := Exit{RoomID: int(toRoomID)}
+		if toWorldID != worldID {
+			exit.WorldID = string(toWorldID)
+		}
+
+		if room.Exits == nil {
+			room.Exits = make(map[string]Exit)
+		}
+		room.Exits[direction.String()] = exit
+		return nil
+	})
+}
+
+// UnlinkExit removes the compass exit. Exits the room inherits from its template are removed by saving an exit to room 0.
+func (w *DataWriter) UnlinkExit(worldID model.WorldID, roomID model.RoomID, direction model.Direction) error {
+	return w.editRoom(worldID, roomID, func(room *Room) error {
+		template, err := resolveRoom(&Room{Template: room.Template}, w.dw.templates)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := template.Exits[direction.String()]; ok {
+			if room.Exits == nil {
+				room.Exits = make(map[string]Exit)
+			}
+			room.Exits[direction.String()] = Exit{}
+			return nil
+		}
+
+		if _, ok := room.Exits[direction.String()]; !ok {
+			return errors.New("the room file has no exit " + direction.String())
+		}
+		delete(room.Exits, direction.String())
+		return nil
+	})
+}
+
+// editRoom reads the room's file, changes it, saves it and updates the room in the simulation.
+func (w *DataWriter) editRoom(worldID model.WorldID, roomID model.RoomID, edit func(room *Room) error) error {
+	roomPath, room, err := w.readRoom(worldID, roomID)
+	if err != nil {
+		return err
+	}
+
+	if err := edit(room); err != nil {
+		return err
+	}
+
+	if err := writeDataFile(roomPath, room); err != nil {
+		return err
+	}
+
+	return w.dw.updateRoomInSim(worldID, roomID, room)
+}
+
+func (w *DataWriter) readRoom(worldID model.WorldID, roomID model.RoomID) (string, *Room, error) {
+	roomPath, ok := findFile(w.worldFolder(worldID), int(roomID))
+	if !ok {
+		return "", nil, fmt.Errorf("there is no file for room %d in world '%s'", roomID, worldID)
+	}
+
+	room, err := loadRoom(roomPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return roomPath, room, nil
+}
+
+func (w *DataWriter) worldFolder(worldID model.WorldID) string {
+	return path.Join(w.dw.dataFolder, "rooms", string(worldID))
+}
+
+// CreateItemDefinition saves a new item definition with nothing but a name, at the next free ID.
+func (w *DataWriter) CreateItemDefinition(name string) (model.ItemDefinitionID, error) {
+	if err := checkName(name); err != nil {
+		return 0, err
+	}
+
+	itemsFolder := path.Join(w.dw.dataFolder, "items")
+
+	itemID, err := nextID(itemsFolder)
+	if err != nil {
+		return 0, err
+	}
+
+	item := &Item{Name: name}
+	if err := writeDataFile(path.Join(itemsFolder, fileName(itemID, name, folderExtension(itemsFolder))), item); err != nil {
+		return 0, err
+	}
+
+	if err := w.dw.addItemToSim(model.ItemDefinitionID(itemID), item); err != nil {
+		return 0, err
+	}
+
+	return model.ItemDefinitionID(itemID), nil
+}
+
+// EditItemDefinition changes one field of the item definition, using the same field names as the item files.
+// Items that already exist in the world change with it.
+func (w *DataWriter) EditItemDefinition(id model.ItemDefinitionID, field, value string) error {
+	itemsFolder := path.Join(w.dw.dataFolder, "items")
+
+	itemPath, ok := findFile(itemsFolder, int(id))
+	if !ok {
+		return fmt.Errorf("there is no file for item %d", id)
+	}
+
+	item, err := loadItem(itemPath)
+	if err != nil {
+		return err
+	}
+
+	if err := setItemField(item, field, value); err != nil {
+		return err
+	}
+
+	// make sure the item would load before saving it
+	if _, _, err := item.rigSlots(); err != nil {
+		return err
+	}
+
+	newPath := path.Join(itemsFolder, fileName(int(id), item.Name, filepath.Ext(itemPath)))
+	if err := writeDataFile(newPath, item); err != nil {
+		return err
+	}
+	if newPath != itemPath {
+		if err := os.Remove(itemPath); err != nil {
+			return err
+		}
+	}
+
+	return w.dw.updateItemInSim(id, item)
+}
+
+// Reload loads changes made to the data folder outside of the game straight away.
+// It is run by a builder's command, so the simulation is already held still.
+func (w *DataWriter) Reload() error {
+	return w.dw.reload()
+}
+
+// setItemField changes an item file field from text. Lists are separated by commas.
+func setItemField(item *Item, field, value string) error {
+	var err error
+
+	switch strings.ToLower(field) {
+	case "name":
+		if err := checkName(value); err != nil {
+			return err
+		}
+		item.Name = value
+	case "aliases":
+		item.Aliases = splitList(value)
+	case "weight":
+		item.Weight, err = strconv.ParseInt(value, 10, 64)
+	case "rigslot", "rig_slot":
+		item.RigSlot = value
+	case "occupies":
+		item.Occupies = splitList(value)
+	case "two_handed":
+		item.TwoHanded, err = strconv.ParseBool(value)
+	case "short_description":
+		item.ShortDescription = value
+	case "long_description":
+		item.LongDescription = value
+	case "stackable":
+		item.Stackable, err = strconv.ParseBool(value)
+	case "plural":
+		item.Plural = value
+	case "value":
+		item.Value, err = strconv.ParseInt(value, 10, 64)
+	default:
+		return fmt.Errorf("items have no field '%s'", field)
+	}
+
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid %s", value, field)
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// checkName makes sure a name can be used in a file name.
+func checkName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("it needs a name")
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" '-,", r) {
+			return fmt.Errorf("names can't contain '%c'", r)
+		}
+	}
+
+	return nil
+}
+
+// fileName is the "X Name.toml" file name that the loaders read IDs from, in the format of the extension.
+func fileName(id int, name string, ext string) string {
+	return fmt.Sprintf("%d %s%s", id, name, ext)
+}
+
+// findFile finds the data file in the folder with the ID.
+func findFile(folder string, id int) (string, bool) {
+	files, _, err := listDataFiles(folder)
+	if err != nil {
+		return "", false
+	}
+
+	filePath, ok := files[id]
+	return filePath, ok
+}
+
+// nextID returns one more than the highest ID of the data files in the folder.
+func nextID(folder string) (int, error) {
+	files, _, err := listDataFiles(folder)
+	if err != nil {
+		return 0, err
+	}
+
+	highest := 0
+	for id := range files {
+		if id > highest {
+			highest = id
+		}
+	}
+
+	return highest + 1, nil
+}
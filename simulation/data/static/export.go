@@ -0,0 +1,79 @@
+package static
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Export copies the data folder into the export folder with every data file converted to the format, which is toml, json or yaml.
+// Scripts and other files that aren't data are copied as they are. The export folder must be empty or not exist yet.
+func Export(dataFolder, exportFolder, format string) error {
+	ext := "." + strings.TrimPrefix(strings.ToLower(format), ".")
+	if !isDataFile(ext) {
+		return fmt.Errorf("'%s' is not a data format, it can be toml, json or yaml", format)
+	}
+
+	// exporting into the data folder would export the export
+	if rel, err := filepath.Rel(dataFolder, exportFolder); err == nil && !strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("the export folder can't be inside the data folder")
+	}
+	if entries, err := os.ReadDir(exportFolder); err == nil && len(entries) > 0 {
+		return fmt.Errorf("the export folder '%s' is not empty", exportFolder)
+	}
+
+	return filepath.WalkDir(dataFolder, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dataFolder, filePath)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(exportFolder, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		if !isDataFile(d.Name()) {
+			return copyFile(filePath, target)
+		}
+
+		// the fields are converted as they are, so that nothing is lost even if this version doesn't know about it
+		var fields map[string]interface{}
+		if err := decodeFile(filePath, &fields); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+
+		target = dataFileName(target) + ext
+		if fileExists(target) {
+			return fmt.Errorf("%s: another file in the folder has the same name, so they would both be exported as %s", rel, filepath.Base(target))
+		}
+
+		return writeDataFile(target, fields)
+	})
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,127 @@
+package static
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// getFileID extracts the ID from the file name
+// numbered data files are named "X Name.toml" where X is the ID
+func getFileID(filename string) (int, error) {
+	idString := strings.SplitN(filename, " ", 2)[0]
+	id, err := strconv.Atoi(idString)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// fileErrors are the data files in a folder that couldn't be loaded, with what is wrong with each of them.
+type fileErrors map[string]issue
+
+func (e fileErrors) Error() string {
+	var lines []string
+	for filePath, i := range e {
+		lines = append(lines, fmt.Sprintf("%s: %s", filePath, i.message))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// loadDataFiles calls load with the ID and path of every "X Name.toml" file in the folder, and returns the files by ID.
+// Anything that isn't a data file, such as a script, a readme or a file left behind by a write that didn't finish, is skipped.
+// The files that are badly named or don't load are returned together as fileErrors, after the rest have been loaded.
+func loadDataFiles(folder string, load func(id int, filePath string) error) (map[int]string, error) {
+	files, badFiles, err := listDataFiles(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, filePath := range files {
+		if err := load(id, filePath); err != nil {
+			badFiles[filePath] = issue{"decode", err.Error()}
+			delete(files, id)
+		}
+	}
+
+	if len(badFiles) > 0 {
+		return files, fileErrors(badFiles)
+	}
+	return files, nil
+}
+
+// loadNamedFiles calls load with the name and path of every data file in a folder of files that are named rather than numbered, and returns the files by name.
+// Files that don't load, or that have the same name as another file in a different format, are returned together as fileErrors.
+func loadNamedFiles(folder string, getName func(string) string, load func(name, filePath string) error) (map[string]string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string)
+	badFiles := make(fileErrors)
+	for _, entry := range entries {
+		if entry.IsDir() || !isDataFile(entry.Name()) {
+			continue
+		}
+
+		filePath := path.Join(folder, entry.Name())
+
+		name := getName(entry.Name())
+		if other, ok := files[name]; ok {
+			badFiles[filePath] = issue{"duplicate-name", fmt.Sprintf("the name '%s' is already used by '%s'", name, filepath.Base(other))}
+			continue
+		}
+		files[name] = filePath
+
+		if err := load(name, filePath); err != nil {
+			badFiles[filePath] = issue{"decode", err.Error()}
+		}
+	}
+
+	if len(badFiles) > 0 {
+		return files, badFiles
+	}
+	return files, nil
+}
+
+// listDataFiles finds every "X Name.toml" file in the folder by its ID.
+// Files with no ID at the start of their name, or with an ID that another file already has, are returned with what is wrong with them.
+func listDataFiles(folder string) (map[int]string, map[string]issue, error) {
+	files := make(map[int]string)
+	badFiles := make(map[string]issue)
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return files, badFiles, err
+	}
+
+	for _, entry := range entries {
+		// the world file sits with the rooms, but it is not a room
+		if entry.IsDir() || !isDataFile(entry.Name()) || isWorldFile(entry.Name()) {
+			continue
+		}
+
+		filePath := path.Join(folder, entry.Name())
+
+		id, err := getFileID(entry.Name())
+		if err != nil {
+			badFiles[filePath] = issue{"file-name", "the file name must start with its ID"}
+			continue
+		}
+
+		if other, ok := files[id]; ok {
+			badFiles[filePath] = issue{"duplicate-id", fmt.Sprintf("ID %d is already used by '%s'", id, filepath.Base(other))}
+			continue
+		}
+
+		files[id] = filePath
+	}
+
+	return files, badFiles, nil
+}
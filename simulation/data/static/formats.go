@@ -0,0 +1,210 @@
+package static
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultExtension is the format of new data files when there are no others to match.
+const defaultExtension = ".toml"
+
+// dataExtensions are the formats that data files can be written in.
+// They all have the same schema: JSON and YAML are read by turning them into TOML first, so the toml field tags name the fields in every format.
+var dataExtensions = []string{".toml", ".json", ".yaml", ".yml"}
+
+// isDataFile checks whether the file name is in one of the data formats.
+func isDataFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range dataExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// dataFileName removes the format from the file name, so "1 Hall.json" is "1 Hall"
+func dataFileName(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// scriptPath is the Lua script that goes with a data file, which has the same name.
+func scriptPath(filePath string) string {
+	return dataFileName(filePath) + ".lua"
+}
+
+// findNamedFile finds the data file in the folder with the name, in whichever format it is in.
+func findNamedFile(folder, name string) (string, bool) {
+	for _, ext := range dataExtensions {
+		filePath := path.Join(folder, name+ext)
+		if fileExists(filePath) {
+			return filePath, true
+		}
+	}
+	return "", false
+}
+
+// folderExtension is the format of the data files already in the folder, so that new files match them.
+func folderExtension(folder string) string {
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return defaultExtension
+	}
+
+	for _, file := range files {
+		if !file.IsDir() && isDataFile(file.Name()) {
+			return filepath.Ext(file.Name())
+		}
+	}
+	return defaultExtension
+}
+
+// decodeFile reads the data file into v, in the format its extension says it is in.
+func decodeFile(filePath string, v interface{}) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		var fields map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		// numbers are kept as they were written, so that whole numbers can go into int fields
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return err
+		}
+		return decodeFields(fields, v)
+
+	case ".yaml", ".yml":
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		return decodeFields(fields, v)
+
+	default:
+		_, err := toml.Decode(string(data), v)
+		return err
+	}
+}
+
+// decodeFields decodes JSON or YAML fields into v as if they had been written as TOML.
+func decodeFields(fields map[string]interface{}, v interface{}) error {
+	buffer := bytes.Buffer{}
+	if err := toml.NewEncoder(&buffer).Encode(tomlValue(fields)); err != nil {
+		return err
+	}
+
+	_, err := toml.Decode(buffer.String(), v)
+	return err
+}
+
+// tomlValue changes decoded JSON and YAML values into ones that TOML can encode.
+// TOML has no null, so fields that are null are left out as if they weren't there.
+func tomlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+
+	case map[string]interface{}:
+		fields := make(map[string]interface{})
+		for key, value := range v {
+			if value != nil {
+				fields[key] = tomlValue(value)
+			}
+		}
+		return fields
+
+	// YAML maps can have keys that aren't strings, such as numbers
+	case map[interface{}]interface{}:
+		fields := make(map[string]interface{})
+		for key, value := range v {
+			if value != nil {
+				fields[fmt.Sprint(key)] = tomlValue(value)
+			}
+		}
+		return fields
+
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			if value != nil {
+				list = append(list, tomlValue(value))
+			}
+		}
+		return list
+	}
+
+	return value
+}
+
+// encode writes v in the format of the extension, with the same field names in every format.
+func encode(w io.Writer, ext string, v interface{}) error {
+	switch strings.ToLower(ext) {
+	case ".json", ".yaml", ".yml":
+		// the TOML field tags are the schema, so v goes through TOML to get the fields it would have there
+		buffer := bytes.Buffer{}
+		if err := toml.NewEncoder(&buffer).Encode(v); err != nil {
+			return err
+		}
+		var fields map[string]interface{}
+		if _, err := toml.Decode(buffer.String(), &fields); err != nil {
+			return err
+		}
+
+		if strings.ToLower(ext) == ".json" {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(fields)
+		}
+
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(fields); err != nil {
+			return err
+		}
+		return encoder.Close()
+
+	default:
+		return toml.NewEncoder(w).Encode(v)
+	}
+}
+
+// writeDataFile writes the data file in the format of its extension, through a temporary file so that a failed write doesn't leave half a file behind.
+func writeDataFile(filePath string, v interface{}) error {
+	tmpPath := filePath + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if err := encode(f, filepath.Ext(filePath), v); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, filePath)
+}
@@ -1,20 +1,32 @@
 package static
 
 import (
-	"io/ioutil"
-	"path"
-	"strconv"
-	"strings"
+	"errors"
 
-	"github.com/BurntSushi/toml"
+	"github.com/soupstoregames/coda-mud/simulation/model"
 )
 
 type Item struct {
 	Name      string
-	Aliases   []string
-	Weight    int64
-	Container *Container
-	RigSlot   string
+	Aliases   []string   `toml:",omitempty"`
+	Weight    int64      `toml:",omitzero"`
+	Container *Container `toml:",omitempty"`
+	RigSlot   string     `toml:",omitempty"`
+	// Occupies lists any rig slots the item takes up in addition to RigSlot
+	Occupies []string `toml:",omitempty"`
+	// TwoHanded items are held in the main hand and also take up the off hand
+	TwoHanded bool `toml:"two_handed,omitempty"`
+
+	ShortDescription string `toml:"short_description,omitempty"`
+	LongDescription  string `toml:"long_description,omitempty"`
+
+	// Stackable items merge into one item with a quantity, such as arrows or coins
+	Stackable bool `toml:",omitempty"`
+	// Plural is the name used for a stack of more than one, it defaults to the name with an s on the end
+	Plural string `toml:",omitempty"`
+
+	// Value is the base price shops buy and sell the item for
+	Value int64 `toml:",omitzero"`
 }
 
 type Container struct {
@@ -23,52 +35,72 @@ type Container struct {
 func loadAllItems(itemsBaseFolder string) (map[int]*Item, error) {
 	items := make(map[int]*Item)
 
-	// read all of the files in the items folder
-	files, err := ioutil.ReadDir(itemsBaseFolder)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, file := range files {
-		// load the item
-		itemID, err := getItemID(file.Name())
-		if err != nil {
-			return nil, err
-		}
-
-		item, err := loadItem(path.Join(itemsBaseFolder, file.Name()))
+	_, err := loadDataFiles(itemsBaseFolder, func(id int, filePath string) error {
+		item, err := loadItem(filePath)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		items[id] = item
+		return nil
+	})
+	return items, err
+}
 
-		// add the items to the map
-		items[itemID] = item
+// loadItem reads the room file data and decodes it from whichever format it is in
+func loadItem(filepath string) (*Item, error) {
+	var item Item
+	if err := decodeFile(filepath, &item); err != nil {
+		return nil, err
 	}
 
-	return items, nil
+	return &item, nil
 }
 
-// getItemID extracts the item ID from the file name
-// items are named "X Name.toml" where X is the item ID
-func getItemID(filename string) (int, error) {
-	itemIDString := strings.SplitN(filename, " ", 2)[0]
-	itemID, err := strconv.Atoi(itemIDString)
+// rigSlots parses the item's rig slot fields into the primary slot and any extra slots it occupies
+func (i *Item) rigSlots() (model.RigSlot, []model.RigSlot, error) {
+	rigSlot, err := model.StringToRigSlot(i.RigSlot)
 	if err != nil {
-		return 0, err
+		return model.RigSlotNone, nil, err
 	}
-	return itemID, nil
-}
 
-// loadItem reads the room file data and decodes the TOML
-func loadItem(filepath string) (*Item, error) {
-	data, err := ioutil.ReadFile(filepath)
-	if err != nil {
-		return nil, err
+	// two handed items default to being held in the main hand
+	if i.TwoHanded && rigSlot == model.RigSlotNone {
+		rigSlot = model.RigSlotMainHand
 	}
-	var item Item
-	if _, err := toml.Decode(string(data), &item); err != nil {
-		return nil, err
+
+	var extraRigSlots []model.RigSlot
+	if i.TwoHanded {
+		switch rigSlot {
+		case model.RigSlotOffHand:
+			extraRigSlots = append(extraRigSlots, model.RigSlotMainHand)
+		default:
+			extraRigSlots = append(extraRigSlots, model.RigSlotOffHand)
+		}
 	}
 
-	return &item, nil
+	for _, s := range i.Occupies {
+		slot, err := model.StringToRigSlot(s)
+		if err != nil {
+			return model.RigSlotNone, nil, err
+		}
+		if slot == model.RigSlotNone || slot == rigSlot || containsRigSlot(extraRigSlots, slot) {
+			continue
+		}
+		extraRigSlots = append(extraRigSlots, slot)
+	}
+
+	if rigSlot == model.RigSlotNone && len(extraRigSlots) > 0 {
+		return model.RigSlotNone, nil, errors.New("item occupies rig slots but has no rig slot")
+	}
+
+	return rigSlot, extraRigSlots, nil
+}
+
+func containsRigSlot(slots []model.RigSlot, slot model.RigSlot) bool {
+	for _, s := range slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,82 @@
+package static
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is how long the data folder has to be left alone before changes are loaded,
+// so that an editor saving several files at once only causes one reload.
+const reloadDebounce = 500 * time.Millisecond
+
+// Watch reloads the data folder whenever the operating system reports that something in it changed.
+// It returns an error if the folder can't be watched, in which case Poll can be used instead.
+func (dw *DataWatcher) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	// fsnotify doesn't watch sub folders, so each one is added
+	if err := watchFolders(watcher, dw.dataFolder); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		var pending <-chan time.Time
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				// new folders, such as a new world, need watching too
+				if event.Has(fsnotify.Create) {
+					if err := watchFolders(watcher, event.Name); err != nil {
+						dw.Errors <- err
+					}
+				}
+
+				pending = time.After(reloadDebounce)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				dw.Errors <- err
+
+			case <-pending:
+				pending = nil
+				if err := dw.Reload(); err != nil {
+					dw.Errors <- err
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchFolders adds the folder and every folder inside it to the watcher. Files are ignored.
+func watchFolders(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(folder string, d fs.DirEntry, err error) error {
+		// files such as an editor's swap files can be gone again before they are looked at
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return watcher.Add(folder)
+	})
+}
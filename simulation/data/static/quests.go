@@ -0,0 +1,103 @@
+package static
+
+import (
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+type Quest struct {
+	Name        string
+	Description string
+	Steps       []QuestStep
+	Rewards     QuestRewards
+}
+
+// QuestStep is one goal of a quest. Type is one of "visit", "obtain", "talk" or "flag" and decides which other fields are used.
+type QuestStep struct {
+	Type        string
+	Description string
+	// WorldID and RoomID are the room to visit, or optionally the room to talk in
+	WorldID  string `toml:"world_id"`
+	RoomID   int    `toml:"room_id"`
+	ItemID   int    `toml:"item_id"`
+	Quantity int
+	NPC      string `toml:"npc"`
+	Flag     string
+}
+
+type QuestRewards struct {
+	Money int64
+	Items []Component
+}
+
+// loadAllQuests loads every quest in the quests folder. The quests folder is optional.
+func loadAllQuests(questsBaseFolder string) (map[int]*Quest, error) {
+	quests := make(map[int]*Quest)
+
+	if !fileExists(questsBaseFolder) {
+		return quests, nil
+	}
+
+	_, err := loadDataFiles(questsBaseFolder, func(id int, filePath string) error {
+		quest, err := loadQuest(filePath)
+		if err != nil {
+			return err
+		}
+		quests[id] = quest
+		return nil
+	})
+	return quests, err
+}
+
+// loadQuest reads the quest file data and decodes it from whichever format it is in
+func loadQuest(filepath string) (*Quest, error) {
+	var quest Quest
+	if err := decodeFile(filepath, &quest); err != nil {
+		return nil, err
+	}
+
+	// a reward without a quantity means one of them
+	for i := range quest.Rewards.Items {
+		if quest.Rewards.Items[i].Quantity == 0 {
+			quest.Rewards.Items[i].Quantity = 1
+		}
+	}
+
+	return &quest, nil
+}
+
+// itemIDs are the items the quest's steps ask for and the items it rewards
+func (q *Quest) itemIDs() []int {
+	var ids []int
+	for _, s := range q.Steps {
+		if stepType, err := model.StringToQuestStepType(s.Type); err == nil && stepType == model.QuestStepObtain {
+			ids = append(ids, s.ItemID)
+		}
+	}
+	for _, c := range q.Rewards.Items {
+		ids = append(ids, c.ItemID)
+	}
+	return ids
+}
+
+// steps converts the quest's steps into their simulation form
+func (q *Quest) steps() ([]*model.QuestStep, error) {
+	var steps []*model.QuestStep
+	for _, s := range q.Steps {
+		stepType, err := model.StringToQuestStepType(s.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, &model.QuestStep{
+			Type:        stepType,
+			Description: s.Description,
+			WorldID:     model.WorldID(s.WorldID),
+			RoomID:      model.RoomID(s.RoomID),
+			ItemID:      model.ItemDefinitionID(s.ItemID),
+			Quantity:    s.Quantity,
+			NPC:         s.NPC,
+			Flag:        s.Flag,
+		})
+	}
+	return steps, nil
+}
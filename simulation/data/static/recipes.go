@@ -0,0 +1,70 @@
+package static
+
+type Recipe struct {
+	Name    string
+	Aliases []string
+	// RoomTag is a tag the room must have to craft the recipe there, such as "forge"
+	RoomTag string `toml:"room_tag"`
+	// Tools are the item IDs of tools that must be carried, they are not used up
+	Tools   []int
+	Inputs  []Component
+	Outputs []Component
+}
+
+type Component struct {
+	ItemID   int `toml:"item_id"`
+	Quantity int
+}
+
+// itemIDs are the items the recipe needs and makes
+func (r *Recipe) itemIDs() []int {
+	ids := append([]int(nil), r.Tools...)
+	for _, c := range r.Inputs {
+		ids = append(ids, c.ItemID)
+	}
+	for _, c := range r.Outputs {
+		ids = append(ids, c.ItemID)
+	}
+	return ids
+}
+
+// loadAllRecipes loads every recipe in the recipes folder. The recipes folder is optional.
+func loadAllRecipes(recipesBaseFolder string) (map[int]*Recipe, error) {
+	recipes := make(map[int]*Recipe)
+
+	if !fileExists(recipesBaseFolder) {
+		return recipes, nil
+	}
+
+	_, err := loadDataFiles(recipesBaseFolder, func(id int, filePath string) error {
+		recipe, err := loadRecipe(filePath)
+		if err != nil {
+			return err
+		}
+		recipes[id] = recipe
+		return nil
+	})
+	return recipes, err
+}
+
+// loadRecipe reads the recipe file data and decodes it from whichever format it is in
+func loadRecipe(filepath string) (*Recipe, error) {
+	var recipe Recipe
+	if err := decodeFile(filepath, &recipe); err != nil {
+		return nil, err
+	}
+
+	// a component without a quantity means one of them
+	for i := range recipe.Inputs {
+		if recipe.Inputs[i].Quantity == 0 {
+			recipe.Inputs[i].Quantity = 1
+		}
+	}
+	for i := range recipe.Outputs {
+		if recipe.Outputs[i].Quantity == 0 {
+			recipe.Outputs[i].Quantity = 1
+		}
+	}
+
+	return &recipe, nil
+}
@@ -0,0 +1,872 @@
+package static
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nicklanng/fsdiff"
+	"github.com/soupstoregames/coda-mud/simulation"
+	"github.com/soupstoregames/coda-mud/simulation/model"
+	"github.com/soupstoregames/go-core/logging"
+)
+
+// ReloadError lists everything that was wrong with the data folder when it was reloaded.
+type ReloadError struct {
+	// Applied is true if the changes were made anyway, because the problems were only found while making them
+	Applied  bool
+	Problems []string
+}
+
+func (e *ReloadError) Error() string {
+	buffer := strings.Builder{}
+	if e.Applied {
+		buffer.WriteString("the data folder was reloaded, but there were problems:")
+	} else {
+		buffer.WriteString("the data folder was not reloaded, nothing was changed because of these problems:")
+	}
+	for _, problem := range e.Problems {
+		buffer.WriteString("\n - ")
+		buffer.WriteString(problem)
+	}
+	return buffer.String()
+}
+
+// changeSet is everything that changed in the data folder since it was last loaded.
+// All of it is read and checked before any of it is applied, so that a mistake in one file doesn't leave the world half changed.
+type changeSet struct {
+	dataFolder string
+
+	items        map[int]*Item
+	removedItems []int
+
+	// addedWorlds are new world folders with all of their rooms
+	addedWorlds   map[string]map[int]*Room
+	removedWorlds []string
+	// worldFiles are the world files of added worlds, and of worlds whose world file changed
+	worldFiles map[string]*World
+	// rooms are the added and changed rooms of worlds that are already loaded
+	rooms        map[string]map[int]*Room
+	removedRooms map[string][]int
+
+	// templates are the added and changed room templates, the rooms that inherit from them are in rooms
+	templates        map[string]*Room
+	removedTemplates []string
+
+	shops              map[int]*Shop
+	removedShops       []int
+	recipes            map[int]*Recipe
+	removedRecipes     []int
+	quests             map[int]*Quest
+	removedQuests      []int
+	socials            map[string]*Social
+	removedSocials     []string
+	backgrounds        map[int]*Background
+	removedBackgrounds []int
+
+	problems []string
+}
+
+// reload reads, checks and applies the changes to the data folder.
+// The caller must be holding the simulation still, either with Update or by running inside a command.
+func (dw *DataWatcher) reload() error {
+	// get current state of data folder
+	newState, err := fsdiff.BuildTree(dw.dataFolder)
+	if err != nil {
+		return err
+	}
+
+	// find the diffed files
+	diff := fsdiff.Compare(dw.lastDataState, newState)
+	if diff.DiffType == fsdiff.DiffTypeNone {
+		return nil
+	}
+
+	changes := dw.readDiff(diff)
+	changes.validate(dw.sim, dw.worlds, dw.templates)
+
+	// leave the last state alone, so that the rejected changes are tried again with the next reload
+	if len(changes.problems) > 0 {
+		return &ReloadError{Problems: changes.problems}
+	}
+
+	dw.lastDataState = newState
+
+	changes.apply(dw)
+	if len(changes.problems) > 0 {
+		return &ReloadError{Applied: true, Problems: changes.problems}
+	}
+
+	return nil
+}
+
+// readDiff reads every added and changed file in the diff, noting the files that can't be read as problems.
+func (dw *DataWatcher) readDiff(diff *fsdiff.Diff) *changeSet {
+	cs := &changeSet{
+		dataFolder:   dw.dataFolder,
+		items:        make(map[int]*Item),
+		addedWorlds:  make(map[string]map[int]*Room),
+		worldFiles:   make(map[string]*World),
+		rooms:        make(map[string]map[int]*Room),
+		removedRooms: make(map[string][]int),
+		templates:    make(map[string]*Room),
+		shops:        make(map[int]*Shop),
+		recipes:      make(map[int]*Recipe),
+		quests:       make(map[int]*Quest),
+		socials:      make(map[string]*Social),
+		backgrounds:  make(map[int]*Background),
+	}
+
+	if items, ok := searchChildrenForName(diff, "items"); ok {
+		var changed map[int]string
+		changed, cs.removedItems = cs.readFiles(items)
+		for id, filePath := range changed {
+			if item, err := loadItem(filePath); err != nil {
+				cs.problem(filePath, err.Error())
+			} else {
+				cs.items[id] = item
+			}
+		}
+	}
+
+	// the templates folder is optional
+	if templates, ok := searchChildrenForName(diff, "templates"); ok {
+		cs.readTemplates(templates)
+	}
+
+	// get room folder
+	if rooms, ok := searchChildrenForName(diff, "rooms"); !ok {
+		cs.problems = append(cs.problems, "there is no rooms folder")
+	} else {
+		cs.readWorlds(rooms)
+	}
+
+	cs.readInheritingRooms(dw)
+
+	// the shops folder is optional
+	if shops, ok := searchChildrenForName(diff, "shops"); ok {
+		var changed map[int]string
+		changed, cs.removedShops = cs.readFiles(shops)
+		for id, filePath := range changed {
+			if shop, err := loadShop(filePath); err != nil {
+				cs.problem(filePath, err.Error())
+			} else {
+				cs.shops[id] = shop
+			}
+		}
+	}
+
+	// the recipes folder is optional
+	if recipes, ok := searchChildrenForName(diff, "recipes"); ok {
+		var changed map[int]string
+		changed, cs.removedRecipes = cs.readFiles(recipes)
+		for id, filePath := range changed {
+			if recipe, err := loadRecipe(filePath); err != nil {
+				cs.problem(filePath, err.Error())
+			} else {
+				cs.recipes[id] = recipe
+			}
+		}
+	}
+
+	// the quests folder is optional
+	if quests, ok := searchChildrenForName(diff, "quests"); ok {
+		var changed map[int]string
+		changed, cs.removedQuests = cs.readFiles(quests)
+		for id, filePath := range changed {
+			if quest, err := loadQuest(filePath); err != nil {
+				cs.problem(filePath, err.Error())
+			} else {
+				cs.quests[id] = quest
+			}
+		}
+	}
+
+	// the socials folder is optional, socials are named by their files rather than numbered
+	if socials, ok := searchChildrenForName(diff, "socials"); ok {
+		for _, social := range socials.Children {
+			if !isDataFile(social.Path) {
+				continue
+			}
+
+			name := getSocialName(filepath.Base(social.Path))
+
+			switch social.DiffType {
+			case fsdiff.DiffTypeAdded, fsdiff.DiffTypeChanged:
+				if s, err := loadSocial(social.Path); err != nil {
+					cs.problem(social.Path, err.Error())
+				} else {
+					cs.socials[name] = s
+				}
+			case fsdiff.DiffTypeRemoved:
+				cs.removedSocials = append(cs.removedSocials, name)
+			}
+		}
+	}
+
+	// the backgrounds folder is optional
+	if backgrounds, ok := searchChildrenForName(diff, "backgrounds"); ok {
+		var changed map[int]string
+		changed, cs.removedBackgrounds = cs.readFiles(backgrounds)
+		for id, filePath := range changed {
+			if background, err := loadBackground(filePath); err != nil {
+				cs.problem(filePath, err.Error())
+			} else {
+				cs.backgrounds[id] = background
+			}
+		}
+	}
+
+	return cs
+}
+
+// readWorlds reads the world folders that were added, and the rooms that changed in the others.
+func (cs *changeSet) readWorlds(diff *fsdiff.Diff) {
+	for _, world := range diff.Children {
+		worldID := filepath.Base(world.Path)
+
+		// there should only be world folders in the rooms folder, ignore anything else
+		if info, err := os.Stat(world.Path); err == nil && !info.IsDir() {
+			continue
+		}
+
+		switch world.DiffType {
+		case fsdiff.DiffTypeAdded:
+			cs.readWorldFile(world.Path)
+
+			rooms := make(map[int]*Room)
+			for id, filePath := range cs.listFiles(world.Path) {
+				if room, err := loadRoom(filePath); err != nil {
+					cs.problem(filePath, err.Error())
+				} else {
+					rooms[id] = room
+				}
+			}
+			cs.addedWorlds[worldID] = rooms
+
+		case fsdiff.DiffTypeRemoved:
+			cs.removedWorlds = append(cs.removedWorlds, worldID)
+
+		case fsdiff.DiffTypeChanged:
+			changed, removed := cs.readFiles(world)
+
+			// the world file's region is used by the world's rooms, so they are all read again with it
+			if worldFileChanged(world) {
+				cs.readWorldFile(world.Path)
+				changed = cs.listFiles(world.Path)
+			}
+
+			cs.rooms[worldID] = make(map[int]*Room)
+			cs.removedRooms[worldID] = removed
+			for id, filePath := range changed {
+				if room, err := loadRoom(filePath); err != nil {
+					cs.problem(filePath, err.Error())
+				} else {
+					cs.rooms[worldID][id] = room
+				}
+			}
+		}
+	}
+}
+
+// readTemplates reads the templates that were added or changed, templates are named by their files rather than numbered.
+func (cs *changeSet) readTemplates(diff *fsdiff.Diff) {
+	for _, file := range diff.Children {
+		name := getTemplateName(filepath.Base(file.Path))
+		filePath := file.Path
+
+		switch {
+		// a template's script is loaded with the template, so a changed script reloads the template
+		case filepath.Ext(file.Path) == ".lua":
+			templatePath, ok := findNamedFile(diff.Path, name)
+			if !ok || file.DiffType == fsdiff.DiffTypeNone {
+				continue
+			}
+			filePath = templatePath
+		case !isDataFile(file.Path):
+			continue
+		case file.DiffType == fsdiff.DiffTypeRemoved:
+			cs.removedTemplates = append(cs.removedTemplates, name)
+			continue
+		case file.DiffType == fsdiff.DiffTypeNone:
+			continue
+		}
+
+		if template, err := loadRoom(filePath); err != nil {
+			cs.problem(filePath, err.Error())
+		} else {
+			cs.templates[name] = template
+		}
+	}
+}
+
+// readInheritingRooms reads the rooms of the loaded worlds that inherit from a changed template, so that they change with it.
+func (cs *changeSet) readInheritingRooms(dw *DataWatcher) {
+	if len(cs.templates) == 0 && len(cs.removedTemplates) == 0 {
+		return
+	}
+
+	changed := make(map[string]bool)
+	for name := range cs.templates {
+		changed[name] = true
+	}
+	for _, name := range cs.removedTemplates {
+		changed[name] = true
+	}
+	merged := cs.mergeTemplates(dw.templates)
+
+	for worldID := range dw.worlds {
+		if cs.worldRemoved(worldID) {
+			continue
+		}
+
+		// badly named files are already noted if they changed, and otherwise were never loaded
+		files, _, err := listDataFiles(path.Join(dw.dataFolder, "rooms", worldID))
+		if err != nil {
+			cs.problem(path.Join(dw.dataFolder, "rooms", worldID), err.Error())
+			continue
+		}
+
+		for roomID, filePath := range files {
+			if _, ok := cs.rooms[worldID][roomID]; ok {
+				continue
+			}
+
+			room, err := loadRoom(filePath)
+			if err != nil {
+				cs.problem(filePath, err.Error())
+				continue
+			}
+
+			// the template may have moved to a different parent, so both the old and new chains count
+			if !inheritsFrom(room, changed, dw.templates) && !inheritsFrom(room, changed, merged) {
+				continue
+			}
+
+			if cs.rooms[worldID] == nil {
+				cs.rooms[worldID] = make(map[int]*Room)
+			}
+			cs.rooms[worldID][roomID] = room
+		}
+	}
+}
+
+// mergeTemplates returns the templates as they will be once the changes are made.
+func (cs *changeSet) mergeTemplates(templates map[string]*Room) map[string]*Room {
+	merged := make(map[string]*Room)
+	for name, template := range templates {
+		merged[name] = template
+	}
+	for _, name := range cs.removedTemplates {
+		delete(merged, name)
+	}
+	for name, template := range cs.templates {
+		merged[name] = template
+	}
+	return merged
+}
+
+func (cs *changeSet) readWorldFile(folder string) {
+	world, err := loadWorld(folder)
+	if err != nil {
+		cs.problem(worldFilePath(folder), err.Error())
+		return
+	}
+	cs.worldFiles[filepath.Base(folder)] = world
+}
+
+func worldFileChanged(diff *fsdiff.Diff) bool {
+	for _, file := range diff.Children {
+		if isWorldFile(filepath.Base(file.Path)) && file.DiffType != fsdiff.DiffTypeNone {
+			return true
+		}
+	}
+	return false
+}
+
+// readFiles finds the files in a folder of "X Name.toml" files that were added or changed, and the IDs that were removed.
+// A file that is removed while another with the same ID is added was renamed, so it is only changed.
+func (cs *changeSet) readFiles(diff *fsdiff.Diff) (map[int]string, []int) {
+	changed := make(map[int]string)
+	var removed []int
+
+	if diff.DiffType == fsdiff.DiffTypeNone {
+		return changed, removed
+	}
+
+	files := make(map[int]string)
+	if diff.DiffType != fsdiff.DiffTypeRemoved {
+		files = cs.listFiles(diff.Path)
+	}
+
+	for _, file := range diff.Children {
+		// a room's script is loaded with the room, so a changed script reloads the room
+		if filepath.Ext(file.Path) == ".lua" {
+			if id, err := getFileID(filepath.Base(file.Path)); err == nil {
+				if filePath, ok := files[id]; ok {
+					changed[id] = filePath
+				}
+			}
+			continue
+		}
+
+		if !isDataFile(file.Path) {
+			continue
+		}
+
+		id, err := getFileID(filepath.Base(file.Path))
+		if err != nil {
+			// listFiles has already noted it if it is still there
+			continue
+		}
+
+		switch file.DiffType {
+		case fsdiff.DiffTypeAdded, fsdiff.DiffTypeChanged:
+			if filePath, ok := files[id]; ok {
+				changed[id] = filePath
+			}
+		case fsdiff.DiffTypeRemoved:
+			if _, ok := files[id]; !ok {
+				removed = append(removed, id)
+			}
+		}
+	}
+
+	return changed, removed
+}
+
+// listFiles finds every "X Name.toml" file in the folder by its ID, noting the files that are badly named.
+func (cs *changeSet) listFiles(folder string) map[int]string {
+	files, badFiles, err := listDataFiles(folder)
+	if err != nil {
+		cs.problem(folder, err.Error())
+	}
+	for filePath, i := range badFiles {
+		cs.problem(filePath, i.message)
+	}
+	return files
+}
+
+// validate checks that the changes would load, and that they fit with the rest of the world.
+// worlds are the world files and templates are the room templates that are already loaded.
+func (cs *changeSet) validate(sim simulation.WorldController, worlds map[string]*World, templates map[string]*Room) {
+	for id, item := range cs.items {
+		if _, _, err := item.rigSlots(); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("item %d: %s", id, err.Error()))
+		}
+	}
+
+	// everything that refers to items must refer to items that are still there once the changes are made
+	for id, shop := range cs.shops {
+		if _, err := shop.restockInterval(); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("shop %d: %s", id, err.Error()))
+		}
+		cs.validateItemIDs(sim, fmt.Sprintf("shop %d", id), shop.itemIDs())
+	}
+	for id, recipe := range cs.recipes {
+		cs.validateItemIDs(sim, fmt.Sprintf("recipe %d", id), recipe.itemIDs())
+	}
+	for id, quest := range cs.quests {
+		if _, err := quest.steps(); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("quest %d: %s", id, err.Error()))
+		}
+		cs.validateItemIDs(sim, fmt.Sprintf("quest %d", id), quest.itemIDs())
+	}
+	for id, background := range cs.backgrounds {
+		cs.validateItemIDs(sim, fmt.Sprintf("background %d", id), background.itemIDs())
+	}
+
+	// an item can only be removed once nothing needs it, apart from the things that are changed or removed with it
+	for _, id := range cs.removedItems {
+		uses, err := sim.ItemDefinitionUses(model.ItemDefinitionID(id))
+		if err != nil {
+			continue
+		}
+		if uses = cs.remainingUses(uses); uses.InUse() {
+			cs.problems = append(cs.problems, fmt.Sprintf("item %d can't be removed, it is still used by %s", id, uses))
+		}
+	}
+
+	templates = cs.mergeTemplates(templates)
+	for name, template := range cs.templates {
+		if _, err := resolveRoom(template, templates); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("template '%s': %s", name, err.Error()))
+		}
+	}
+
+	for worldID, rooms := range cs.addedWorlds {
+		for roomID, room := range rooms {
+			cs.validateRoom(sim, templates, worldID, roomID, room)
+		}
+	}
+	for worldID, rooms := range cs.rooms {
+		for roomID, room := range rooms {
+			cs.validateRoom(sim, templates, worldID, roomID, room)
+		}
+	}
+
+	roomExists := func(worldID string, roomID int) bool {
+		return cs.roomExists(sim, worldID, roomID)
+	}
+	for worldID, world := range cs.worldFiles {
+		for _, i := range checkWorld(worldID, world, roomExists) {
+			cs.problems = append(cs.problems, fmt.Sprintf("world '%s': %s", worldID, i.message))
+		}
+	}
+
+	// there must still be one start world once the changes are made
+	if starts := startWorlds(cs.mergeWorldFiles(worlds)); len(starts) > 1 {
+		sort.Strings(starts)
+		cs.problems = append(cs.problems, fmt.Sprintf("only one world can be the start, but %s all are", strings.Join(starts, ", ")))
+	}
+
+	// rooms that are left alone must not lead into rooms that are going away
+	for _, world := range sim.GetWorlds() {
+		if world.Instance || cs.worldRemoved(string(world.WorldID)) {
+			continue
+		}
+
+		for roomID, room := range world.Rooms {
+			if _, ok := cs.rooms[string(world.WorldID)][int(roomID)]; ok || cs.roomRemoved(string(world.WorldID), int(roomID)) {
+				continue
+			}
+
+			for direction, exit := range room.Exits {
+				if exit != nil && cs.roomRemoved(string(exit.WorldID), int(exit.RoomID)) {
+					cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': the exit %s leads to room %d in world '%s', which is being removed", roomID, world.WorldID, direction.String(), exit.RoomID, exit.WorldID))
+				}
+			}
+			for keyword, exit := range room.NamedExits {
+				if cs.roomRemoved(string(exit.WorldID), int(exit.RoomID)) {
+					cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': the exit '%s' leads to room %d in world '%s', which is being removed", roomID, world.WorldID, keyword, exit.RoomID, exit.WorldID))
+				}
+			}
+		}
+	}
+
+	sort.Strings(cs.problems)
+}
+
+// validateItemIDs notes the item IDs that won't be items once the changes are made.
+func (cs *changeSet) validateItemIDs(sim simulation.WorldController, label string, ids []int) {
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		if seen[id] || cs.itemExists(sim, id) {
+			continue
+		}
+		seen[id] = true
+		cs.problems = append(cs.problems, fmt.Sprintf("%s: there is no item %d", label, id))
+	}
+}
+
+// itemExists checks for the item definition as it will be once the changes are applied.
+func (cs *changeSet) itemExists(sim simulation.WorldController, id int) bool {
+	if _, ok := cs.items[id]; ok {
+		return true
+	}
+	if containsInt(cs.removedItems, id) {
+		return false
+	}
+	_, err := sim.GetItemDefinition(model.ItemDefinitionID(id))
+	return err == nil
+}
+
+// remainingUses leaves out the uses of an item by shops, recipes, quests and backgrounds that are changed or removed,
+// the changed ones have already been checked against the items that will be there.
+func (cs *changeSet) remainingUses(uses simulation.ItemDefinitionUses) simulation.ItemDefinitionUses {
+	remaining := simulation.ItemDefinitionUses{Items: uses.Items}
+	for _, id := range uses.Shops {
+		if _, ok := cs.shops[int(id)]; !ok && !containsInt(cs.removedShops, int(id)) {
+			remaining.Shops = append(remaining.Shops, id)
+		}
+	}
+	for _, id := range uses.Recipes {
+		if _, ok := cs.recipes[int(id)]; !ok && !containsInt(cs.removedRecipes, int(id)) {
+			remaining.Recipes = append(remaining.Recipes, id)
+		}
+	}
+	for _, id := range uses.Quests {
+		if _, ok := cs.quests[int(id)]; !ok && !containsInt(cs.removedQuests, int(id)) {
+			remaining.Quests = append(remaining.Quests, id)
+		}
+	}
+	for _, id := range uses.Backgrounds {
+		if _, ok := cs.backgrounds[int(id)]; !ok && !containsInt(cs.removedBackgrounds, int(id)) {
+			remaining.Backgrounds = append(remaining.Backgrounds, id)
+		}
+	}
+	return remaining
+}
+
+func containsInt(list []int, i int) bool {
+	for _, item := range list {
+		if item == i {
+			return true
+		}
+	}
+	return false
+}
+
+func (cs *changeSet) validateRoom(sim simulation.WorldController, templates map[string]*Room, worldID string, roomID int, room *Room) {
+	// the room is checked with everything it inherits
+	room, err := resolveRoom(room, templates)
+	if err != nil {
+		cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': %s", roomID, worldID, err.Error()))
+		return
+	}
+
+	worldExists := func(worldID string) bool {
+		if _, ok := cs.addedWorlds[worldID]; ok {
+			return true
+		}
+		if cs.worldRemoved(worldID) {
+			return false
+		}
+		for _, world := range sim.GetWorlds() {
+			if string(world.WorldID) == worldID {
+				return true
+			}
+		}
+		return false
+	}
+	roomExists := func(worldID string, roomID int) bool {
+		return cs.roomExists(sim, worldID, roomID)
+	}
+
+	for _, i := range checkRoom(worldID, room, worldExists, roomExists) {
+		cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': %s", roomID, worldID, i.message))
+	}
+}
+
+// mergeWorldFiles returns the world files as they will be once the changes are made.
+func (cs *changeSet) mergeWorldFiles(worlds map[string]*World) map[string]*World {
+	merged := make(map[string]*World)
+	for worldID, world := range worlds {
+		if !cs.worldRemoved(worldID) {
+			merged[worldID] = world
+		}
+	}
+	for worldID, world := range cs.worldFiles {
+		merged[worldID] = world
+	}
+	return merged
+}
+
+// roomExists checks for the room as it will be once the changes are applied.
+func (cs *changeSet) roomExists(sim simulation.WorldController, worldID string, roomID int) bool {
+	if rooms, ok := cs.addedWorlds[worldID]; ok {
+		_, ok := rooms[roomID]
+		return ok
+	}
+
+	if cs.roomRemoved(worldID, roomID) {
+		return false
+	}
+
+	if _, ok := cs.rooms[worldID][roomID]; ok {
+		return true
+	}
+
+	_, err := sim.GetRoom(model.WorldID(worldID), model.RoomID(roomID))
+	return err == nil
+}
+
+func (cs *changeSet) roomRemoved(worldID string, roomID int) bool {
+	if cs.worldRemoved(worldID) {
+		return true
+	}
+
+	for _, id := range cs.removedRooms[worldID] {
+		if id == roomID {
+			return true
+		}
+	}
+	return false
+}
+
+func (cs *changeSet) worldRemoved(worldID string) bool {
+	for _, id := range cs.removedWorlds {
+		if id == worldID {
+			return true
+		}
+	}
+	return false
+}
+
+// apply makes the changes to the simulation.
+// Anything that still fails is noted as a problem, but it is too late to go back.
+func (cs *changeSet) apply(dw *DataWatcher) {
+	// items go first, the rest of the data refers to them
+	for id, item := range cs.items {
+		if err := dw.updateItemInSim(model.ItemDefinitionID(id), item); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("item %d: %s", id, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Loaded item %d", id))
+	}
+
+	// templates go before the rooms that inherit from them
+	dw.templates = cs.mergeTemplates(dw.templates)
+	for name := range cs.templates {
+		logging.Info(fmt.Sprintf("Loaded template %s", name))
+	}
+	for _, name := range cs.removedTemplates {
+		if _, ok := cs.templates[name]; !ok {
+			logging.Info(fmt.Sprintf("Removed template %s", name))
+		}
+	}
+
+	for _, worldID := range cs.removedWorlds {
+		dw.sim.DestroyWorld(model.WorldID(worldID))
+		delete(dw.worlds, worldID)
+		logging.Info(fmt.Sprintf("Removed world '%s'", worldID))
+	}
+
+	for worldID, rooms := range cs.addedWorlds {
+		world, ok := cs.worldFiles[worldID]
+		if !ok {
+			world = &World{}
+		}
+		if err := dw.addWorldToSim(model.WorldID(worldID), world, rooms); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("world '%s': %s", worldID, err.Error()))
+		}
+	}
+
+	// changed world files go before their rooms, which use the world's region
+	for worldID, world := range cs.worldFiles {
+		if _, ok := cs.addedWorlds[worldID]; ok {
+			continue
+		}
+		if err := dw.updateWorldInSim(model.WorldID(worldID), world); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("world '%s': %s", worldID, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Updated world '%s'", worldID))
+	}
+
+	for worldID, roomIDs := range cs.removedRooms {
+		for _, roomID := range roomIDs {
+			if err := dw.sim.DestroyRoom(model.WorldID(worldID), model.RoomID(roomID)); err != nil {
+				cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': %s", roomID, worldID, err.Error()))
+				continue
+			}
+			logging.Info(fmt.Sprintf("Removed room %d in world '%s'", roomID, worldID))
+		}
+	}
+
+	for worldID, rooms := range cs.rooms {
+		for roomID, room := range rooms {
+			wID, rID := model.WorldID(worldID), model.RoomID(roomID)
+
+			// a renamed room file looks like a new file, but the room is already loaded
+			if _, err := dw.sim.GetRoom(wID, rID); err == nil {
+				if err := dw.updateRoomInSim(wID, rID, room); err != nil {
+					cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': %s", roomID, worldID, err.Error()))
+					continue
+				}
+				logging.Info(fmt.Sprintf("Updated room %d in world '%s'", roomID, worldID))
+				continue
+			}
+
+			if err := dw.addRoomToSim(wID, rID, room); err != nil {
+				cs.problems = append(cs.problems, fmt.Sprintf("room %d in world '%s': %s", roomID, worldID, err.Error()))
+				continue
+			}
+			logging.Info(fmt.Sprintf("Added room %d to world '%s'", roomID, worldID))
+		}
+	}
+
+	// the start world may have changed
+	if len(cs.worldFiles) > 0 || len(cs.removedWorlds) > 0 {
+		if err := dw.sim.SetSpawnRoom(dw.SpawnRoom()); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("the spawn room: %s", err.Error()))
+		}
+	}
+
+	for id, shop := range cs.shops {
+		if err := dw.addShopToSim(model.ShopID(id), shop); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("shop %d: %s", id, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Loaded shop %d", id))
+	}
+	for _, id := range cs.removedShops {
+		dw.sim.DestroyShop(model.ShopID(id))
+		logging.Info(fmt.Sprintf("Removed shop %d", id))
+	}
+
+	for id, recipe := range cs.recipes {
+		if err := dw.addRecipeToSim(model.RecipeID(id), recipe); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("recipe %d: %s", id, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Loaded recipe %d", id))
+	}
+	for _, id := range cs.removedRecipes {
+		dw.sim.DestroyRecipe(model.RecipeID(id))
+		logging.Info(fmt.Sprintf("Removed recipe %d", id))
+	}
+
+	for id, quest := range cs.quests {
+		if err := dw.addQuestToSim(model.QuestID(id), quest); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("quest %d: %s", id, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Loaded quest %d", id))
+	}
+	for _, id := range cs.removedQuests {
+		dw.sim.DestroyQuest(model.QuestID(id))
+		logging.Info(fmt.Sprintf("Removed quest %d", id))
+	}
+
+	for name, social := range cs.socials {
+		dw.addSocialToSim(name, social)
+		logging.Info(fmt.Sprintf("Loaded social %s", name))
+	}
+	for _, name := range cs.removedSocials {
+		if _, ok := cs.socials[name]; ok {
+			continue
+		}
+		dw.sim.DestroySocial(name)
+		logging.Info(fmt.Sprintf("Removed social %s", name))
+	}
+
+	for id, background := range cs.backgrounds {
+		if err := dw.addBackgroundToSim(model.BackgroundID(id), background); err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("background %d: %s", id, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Loaded background %d", id))
+	}
+	for _, id := range cs.removedBackgrounds {
+		dw.sim.DestroyBackground(model.BackgroundID(id))
+		logging.Info(fmt.Sprintf("Removed background %d", id))
+	}
+
+	// items go last, once nothing that is changed or removed with them still needs them
+	for _, id := range cs.removedItems {
+		uses, err := dw.sim.DestroyItemDefinition(model.ItemDefinitionID(id))
+		if err == simulation.ErrItemDefinitionInUse {
+			cs.problems = append(cs.problems, fmt.Sprintf("item %d was not removed, it is still used by %s", id, uses))
+			continue
+		}
+		if err != nil {
+			cs.problems = append(cs.problems, fmt.Sprintf("item %d: %s", id, err.Error()))
+			continue
+		}
+		logging.Info(fmt.Sprintf("Removed item %d", id))
+	}
+
+	sort.Strings(cs.problems)
+}
+
+// problem notes something wrong with a file, named relative to the data folder.
+func (cs *changeSet) problem(filePath, problem string) {
+	if rel, err := filepath.Rel(cs.dataFolder, filePath); err == nil {
+		filePath = rel
+	}
+	cs.problems = append(cs.problems, fmt.Sprintf("%s: %s", filePath, problem))
+}
@@ -4,26 +4,47 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
-	"path/filepath"
-	"strconv"
-	"strings"
-
-	"github.com/BurntSushi/toml"
 )
 
 type Room struct {
-	Name        string
-	Region      string
-	Description string
+	// Template is the name of the room in the templates folder that this room inherits from
+	Template string `toml:",omitempty"`
+
+	Name        string `toml:",omitempty"`
+	Region      string `toml:",omitempty"`
+	Description string `toml:",omitempty"`
 
-	Exits map[string]Exit
+	Exits      map[string]Exit `toml:",omitempty"`
+	NamedExits map[string]Exit `toml:"named_exits,omitempty"`
+	Extras     []Extra         `toml:"extras,omitempty"`
+
+	// Shop is the ID of the shop that trades in this room
+	Shop int `toml:",omitzero"`
+	// Tags mark the room as having something special, such as a forge for crafting
+	Tags []string `toml:",omitempty"`
 
 	Script string `toml:"-"`
 }
 
+// Extra is a keyword-triggered description of something in the room, shown with "look <keyword>".
+type Extra struct {
+	Keywords    []string `toml:"keywords"`
+	Description string   `toml:"description"`
+}
+
 type Exit struct {
 	RoomID  int    `toml:"room_id"`
-	WorldID string `toml:"world_id"`
+	WorldID string `toml:"world_id,omitempty"`
+	// Door is the starting state of a door in the exit, "open", "closed" or "locked". Leave empty for no door.
+	Door string `toml:"door,omitempty"`
+	// Key is the item ID of the key that locks and unlocks the door
+	Key int `toml:"key,omitzero"`
+	// Name describes a named exit to players, such as "a shimmering portal". It defaults to the exit's keyword.
+	Name string `toml:"name,omitempty"`
+	// Hidden exits have to be found with search, or revealed by a script
+	Hidden bool `toml:"hidden,omitempty"`
+	// Condition is the name of a function in the room's Lua script that can refuse passage through the exit
+	Condition string `toml:"condition,omitempty"`
 }
 
 // loadRooms will scan through all world folders and load the TOML room files
@@ -43,7 +64,7 @@ func loadAllWorlds(roomBaseFolder string) (map[string]map[int]*Room, error) {
 		}
 
 		// load the world rooms
-		world, err := loadWorldFolder(path.Join(roomBaseFolder, file.Name()))
+		world, _, err := loadWorldFolder(path.Join(roomBaseFolder, file.Name()))
 		if err != nil {
 			return nil, err
 		}
@@ -56,75 +77,39 @@ func loadAllWorlds(roomBaseFolder string) (map[string]map[int]*Room, error) {
 }
 
 // load world takes a path to a folder full of rooms
-// it will go through each room and load it into a map
-func loadWorldFolder(folder string) (map[int]*Room, error) {
+// it will go through each room and load it into a map, along with the file each room came from
+func loadWorldFolder(folder string) (map[int]*Room, map[int]string, error) {
 	rooms := make(map[int]*Room)
 
 	file, err := os.Stat(folder)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !file.IsDir() {
-		return nil, nil
-	}
-
-	// get all of the room files from the world folder
-	files, err := ioutil.ReadDir(folder)
-	if err != nil {
-		return nil, err
+		return nil, nil, nil
 	}
 
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		if filepath.Ext(file.Name()) != roomExtension {
-			continue
-		}
-
-		roomID, err := getRoomID(file.Name())
-		if err != nil {
-			return nil, err
-		}
-
-		room, err := loadRoom(path.Join(folder, file.Name()))
+	files, err := loadDataFiles(folder, func(id int, filePath string) error {
+		room, err := loadRoom(filePath)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		rooms[roomID] = room
-	}
-
-	return rooms, nil
-}
-
-// getRoomID extracts the room ID from the file name
-// rooms are named "X Name.toml" where X is the room ID
-func getRoomID(filename string) (int, error) {
-	roomIDString := strings.SplitN(filename, " ", 2)[0]
-	roomID, err := strconv.Atoi(roomIDString)
-	if err != nil {
-		return 0, err
-	}
-	return roomID, nil
+		rooms[id] = room
+		return nil
+	})
+	return rooms, files, err
 }
 
-// loadRoom reads the room file data and decodes the TOML
+// loadRoom reads the room file data and decodes it from whichever format it is in
 func loadRoom(filepath string) (*Room, error) {
-	data, err := ioutil.ReadFile(filepath)
-	if err != nil {
-		return nil, err
-	}
 	var room Room
-	if _, err := toml.Decode(string(data), &room); err != nil {
+	if err := decodeFile(filepath, &room); err != nil {
 		return nil, err
 	}
 
 	// check for and load lua file
-	ext := path.Ext(filepath)
-	luaPath := filepath[0:len(filepath)-len(ext)] + ".lua"
+	luaPath := scriptPath(filepath)
 	if fileExists(luaPath) {
 		contents, err := ioutil.ReadFile(luaPath)
 		if err != nil {
@@ -0,0 +1,81 @@
+package static
+
+import (
+	"time"
+)
+
+const (
+	defaultBuyMultiplier  = 1.0
+	defaultSellMultiplier = 0.5
+)
+
+type Shop struct {
+	Name string
+	// Keeper is the name of the vendor who runs the shop
+	Keeper string
+	// BuyMultiplier is applied to item values to get the price players pay, it defaults to 1
+	BuyMultiplier float64 `toml:"buy_multiplier"`
+	// SellMultiplier is applied to item values to get the price players are paid, it defaults to 0.5
+	SellMultiplier float64 `toml:"sell_multiplier"`
+	// Restock is how often the shop restocks, such as "10m". Leave empty to never restock.
+	Restock string
+	Stock   []Stock
+}
+
+type Stock struct {
+	ItemID   int `toml:"item_id"`
+	Quantity int
+}
+
+// loadAllShops loads every shop in the shops folder. The shops folder is optional.
+func loadAllShops(shopsBaseFolder string) (map[int]*Shop, error) {
+	shops := make(map[int]*Shop)
+
+	if !fileExists(shopsBaseFolder) {
+		return shops, nil
+	}
+
+	_, err := loadDataFiles(shopsBaseFolder, func(id int, filePath string) error {
+		shop, err := loadShop(filePath)
+		if err != nil {
+			return err
+		}
+		shops[id] = shop
+		return nil
+	})
+	return shops, err
+}
+
+// loadShop reads the shop file data and decodes it from whichever format it is in
+func loadShop(filepath string) (*Shop, error) {
+	var shop Shop
+	if err := decodeFile(filepath, &shop); err != nil {
+		return nil, err
+	}
+
+	if shop.BuyMultiplier == 0 {
+		shop.BuyMultiplier = defaultBuyMultiplier
+	}
+	if shop.SellMultiplier == 0 {
+		shop.SellMultiplier = defaultSellMultiplier
+	}
+
+	return &shop, nil
+}
+
+// itemIDs are the items the shop stocks
+func (s *Shop) itemIDs() []int {
+	var ids []int
+	for _, stock := range s.Stock {
+		ids = append(ids, stock.ItemID)
+	}
+	return ids
+}
+
+// restockInterval parses the shop's restock duration
+func (s *Shop) restockInterval() (time.Duration, error) {
+	if s.Restock == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s.Restock)
+}
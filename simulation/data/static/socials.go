@@ -0,0 +1,64 @@
+package static
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// Social holds the message templates for a social. In the templates $n is the character performing it and $N is the target.
+type Social struct {
+	Untargeted SocialMessages
+	Targeted   SocialMessages
+	Self       SocialMessages
+}
+
+type SocialMessages struct {
+	Actor  string
+	Target string
+	Others string
+}
+
+// loadAllSocials loads every social in the socials folder. The socials folder is optional.
+func loadAllSocials(socialsBaseFolder string) (map[string]*Social, error) {
+	socials := make(map[string]*Social)
+
+	if !fileExists(socialsBaseFolder) {
+		return socials, nil
+	}
+
+	_, err := loadNamedFiles(socialsBaseFolder, getSocialName, func(name, filePath string) error {
+		social, err := loadSocial(filePath)
+		if err != nil {
+			return err
+		}
+		socials[name] = social
+		return nil
+	})
+	return socials, err
+}
+
+// getSocialName extracts the social's name from the file name
+// socials are named "name.toml" where name is the word players type to perform it
+func getSocialName(filename string) string {
+	return strings.ToLower(strings.TrimSuffix(filename, filepath.Ext(filename)))
+}
+
+// loadSocial reads the social file data and decodes it from whichever format it is in
+func loadSocial(filepath string) (*Social, error) {
+	var social Social
+	if err := decodeFile(filepath, &social); err != nil {
+		return nil, err
+	}
+
+	return &social, nil
+}
+
+func (m SocialMessages) toModel() model.SocialMessages {
+	return model.SocialMessages{
+		Actor:  m.Actor,
+		Target: m.Target,
+		Others: m.Others,
+	}
+}
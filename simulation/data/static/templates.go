@@ -0,0 +1,167 @@
+package static
+
+import (
+	"fmt"
+	"strings"
+)
+
+// templateDescription in a room's description is replaced with the description of its template
+const templateDescription = "{template}"
+
+// loadAllTemplates loads the room templates in the templates folder, which is optional, along with the file each came from.
+// Templates are rooms that other rooms inherit from, named after their files rather than numbered.
+func loadAllTemplates(templatesBaseFolder string) (map[string]*Room, map[string]string, error) {
+	templates := make(map[string]*Room)
+
+	if !fileExists(templatesBaseFolder) {
+		return templates, nil, nil
+	}
+
+	files, err := loadNamedFiles(templatesBaseFolder, getTemplateName, func(name, filePath string) error {
+		template, err := loadRoom(filePath)
+		if err != nil {
+			return err
+		}
+		templates[name] = template
+		return nil
+	})
+	return templates, files, err
+}
+
+// getTemplateName is the file name without its extension, so "corridor.toml" is the template "corridor"
+func getTemplateName(filename string) string {
+	return dataFileName(filename)
+}
+
+// resolveRoom returns the room with everything it inherits from its template filled in.
+// Fields set in the room replace the template's, while exits, extras and tags are merged with the template's.
+// A room's description can include the template's description with {template}.
+// An exit with room ID 0 removes the exit the template has in that direction.
+func resolveRoom(room *Room, templates map[string]*Room) (*Room, error) {
+	return resolveTemplates(room, templates, make(map[string]bool))
+}
+
+func resolveTemplates(room *Room, templates map[string]*Room, seen map[string]bool) (*Room, error) {
+	if room.Template == "" {
+		return room, nil
+	}
+
+	if seen[room.Template] {
+		return nil, fmt.Errorf("the template '%s' inherits from itself", room.Template)
+	}
+	seen[room.Template] = true
+
+	template, ok := templates[room.Template]
+	if !ok {
+		return nil, fmt.Errorf("there is no template '%s'", room.Template)
+	}
+
+	// templates can have templates of their own
+	base, err := resolveTemplates(template, templates, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeRoom(base, room), nil
+}
+
+// mergeRoom makes a new room from the room and the template it inherits from.
+func mergeRoom(template, room *Room) *Room {
+	merged := *room
+
+	if merged.Name == "" {
+		merged.Name = template.Name
+	}
+	if merged.Region == "" {
+		merged.Region = template.Region
+	}
+	// a room can put its own description around the template's by saying where it goes
+	if merged.Description == "" {
+		merged.Description = template.Description
+	} else {
+		merged.Description = strings.ReplaceAll(merged.Description, templateDescription, template.Description)
+	}
+	if merged.Shop == 0 {
+		merged.Shop = template.Shop
+	}
+	if merged.Script == "" {
+		merged.Script = template.Script
+	}
+
+	merged.Exits = mergeExits(template.Exits, room.Exits)
+	merged.NamedExits = mergeExits(template.NamedExits, room.NamedExits)
+
+	// the room's own extras come first, and replace the template's extras for the same keywords
+	merged.Extras = append([]Extra(nil), room.Extras...)
+	for _, extra := range template.Extras {
+		if !extrasShareKeyword(room.Extras, extra) {
+			merged.Extras = append(merged.Extras, extra)
+		}
+	}
+
+	merged.Tags = append([]string(nil), template.Tags...)
+	for _, tag := range room.Tags {
+		if !containsString(merged.Tags, tag) {
+			merged.Tags = append(merged.Tags, tag)
+		}
+	}
+
+	return &merged
+}
+
+func mergeExits(template, room map[string]Exit) map[string]Exit {
+	if template == nil && room == nil {
+		return nil
+	}
+
+	exits := make(map[string]Exit)
+	for key, exit := range template {
+		exits[key] = exit
+	}
+	for key, exit := range room {
+		if exit.RoomID == 0 {
+			delete(exits, key)
+			continue
+		}
+		exits[key] = exit
+	}
+	return exits
+}
+
+func extrasShareKeyword(extras []Extra, extra Extra) bool {
+	for _, e := range extras {
+		for _, keyword := range e.Keywords {
+			if containsString(extra.Keywords, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritsFrom checks whether the room's template is one of the names, or inherits from one of them.
+func inheritsFrom(room *Room, names map[string]bool, templates map[string]*Room) bool {
+	seen := make(map[string]bool)
+	for name := room.Template; name != "" && !seen[name]; {
+		if names[name] {
+			return true
+		}
+		seen[name] = true
+
+		template, ok := templates[name]
+		if !ok {
+			return false
+		}
+		name = template.Template
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+package static
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is something wrong with a data folder, found by Validate.
+type Problem struct {
+	Severity string `json:"severity"`
+	// Check is the kind of problem, such as "broken-exit", so that tools can pick out the ones they care about
+	Check string `json:"check"`
+	// File is relative to the data folder
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+// issue is something wrong with a room or item, before it is known which file it came from.
+type issue struct {
+	check   string
+	message string
+}
+
+// Validate reads the whole data folder without a simulation, and reports everything that is wrong with it.
+// Rooms that can't be reached by walking from the spawn room are warnings, as are exits with no way back.
+func Validate(dataFolder string) []Problem {
+	v := &validator{dataFolder: dataFolder}
+
+	// the folders are read with the same loaders as the server uses, so that what loads here loads there
+	itemsFolder := path.Join(dataFolder, "items")
+	items, err := loadAllItems(itemsFolder)
+	v.loadErrors(itemsFolder, err)
+	for id, item := range items {
+		if _, _, err := item.rigSlots(); err != nil {
+			filePath, _ := findFile(itemsFolder, id)
+			v.problem(SeverityError, "rig-slot", filePath, fmt.Sprintf("item %d: %s", id, err.Error()))
+		}
+	}
+
+	// the other data folders are optional, and only checked to see that they load
+	_, err = loadAllShops(path.Join(dataFolder, "shops"))
+	v.loadErrors(path.Join(dataFolder, "shops"), err)
+	_, err = loadAllRecipes(path.Join(dataFolder, "recipes"))
+	v.loadErrors(path.Join(dataFolder, "recipes"), err)
+	_, err = loadAllQuests(path.Join(dataFolder, "quests"))
+	v.loadErrors(path.Join(dataFolder, "quests"), err)
+	_, err = loadAllBackgrounds(path.Join(dataFolder, "backgrounds"))
+	v.loadErrors(path.Join(dataFolder, "backgrounds"), err)
+	_, err = loadAllSocials(path.Join(dataFolder, "socials"))
+	v.loadErrors(path.Join(dataFolder, "socials"), err)
+
+	v.validateTemplates()
+	v.validateWorlds()
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		if v.problems[i].File != v.problems[j].File {
+			return v.problems[i].File < v.problems[j].File
+		}
+		return v.problems[i].Message < v.problems[j].Message
+	})
+
+	return v.problems
+}
+
+type validator struct {
+	dataFolder string
+	problems   []Problem
+
+	templates map[string]*Room
+}
+
+// validateTemplates loads the room templates, and checks that each one's chain of templates ends.
+func (v *validator) validateTemplates() {
+	folder := path.Join(v.dataFolder, "templates")
+
+	var files map[string]string
+	var err error
+	v.templates, files, err = loadAllTemplates(folder)
+	v.loadErrors(folder, err)
+
+	for name, template := range v.templates {
+		if _, err := resolveRoom(template, v.templates); err != nil {
+			v.problem(SeverityError, "template", files[name], err.Error())
+		}
+	}
+}
+
+func (v *validator) validateWorlds() {
+	roomsFolder := path.Join(v.dataFolder, "rooms")
+
+	folders, err := os.ReadDir(roomsFolder)
+	if err != nil {
+		v.problem(SeverityError, "rooms-folder", roomsFolder, err.Error())
+		return
+	}
+
+	// load every room in every world
+	worlds := make(map[string]map[int]*Room)
+	worldFiles := make(map[string]*World)
+	roomFiles := make(map[string]map[int]string)
+	for _, folder := range folders {
+		if !folder.IsDir() {
+			continue
+		}
+
+		worldID := folder.Name()
+		worlds[worldID] = make(map[int]*Room)
+
+		world, err := loadWorld(path.Join(roomsFolder, worldID))
+		if err != nil {
+			v.problem(SeverityError, "decode", worldFilePath(path.Join(roomsFolder, worldID)), err.Error())
+			world = &World{}
+		}
+		worldFiles[worldID] = world
+
+		rooms, files, err := loadWorldFolder(path.Join(roomsFolder, worldID))
+		v.loadErrors(path.Join(roomsFolder, worldID), err)
+		roomFiles[worldID] = files
+
+		for id, room := range rooms {
+			// rooms are checked with everything they inherit
+			if resolved, err := resolveRoom(room, v.templates); err != nil {
+				v.problem(SeverityError, "template", files[id], err.Error())
+				room = mergeRoom(&Room{}, room)
+			} else {
+				room = resolved
+			}
+			worlds[worldID][id] = room
+		}
+	}
+
+	worldExists := func(worldID string) bool {
+		_, ok := worlds[worldID]
+		return ok
+	}
+	roomExists := func(worldID string, roomID int) bool {
+		_, ok := worlds[worldID][roomID]
+		return ok
+	}
+
+	for worldID, rooms := range worlds {
+		for roomID, room := range rooms {
+			filePath := roomFiles[worldID][roomID]
+
+			for _, i := range checkRoom(worldID, room, worldExists, roomExists) {
+				// script problems are in the script's file
+				if i.check == "script" {
+					v.problem(SeverityError, i.check, scriptPath(filePath), i.message)
+					continue
+				}
+				v.problem(SeverityError, i.check, filePath, i.message)
+			}
+
+			// compass exits should have a way back
+			for direction, exit := range room.Exits {
+				d, err := model.StringToDirection(direction)
+				if err != nil {
+					continue
+				}
+
+				toWorldID := exitWorldID(worldID, exit)
+				to, ok := worlds[toWorldID][exit.RoomID]
+				if !ok {
+					continue
+				}
+
+				back, ok := to.Exits[d.Opposite().String()]
+				if !ok || exitWorldID(toWorldID, back) != worldID || back.RoomID != roomID {
+					v.problem(SeverityWarning, "one-way-exit", filePath, fmt.Sprintf("the exit %s leads to room %d in world '%s', which has no exit %s back", direction, exit.RoomID, toWorldID, d.Opposite().String()))
+				}
+			}
+		}
+	}
+
+	for worldID, world := range worldFiles {
+		for _, i := range checkWorld(worldID, world, roomExists) {
+			v.problem(SeverityError, i.check, worldFilePath(path.Join(roomsFolder, worldID)), i.message)
+		}
+	}
+
+	if starts := startWorlds(worldFiles); len(starts) > 1 {
+		sort.Strings(starts)
+		v.problem(SeverityError, "start-world", "", fmt.Sprintf("only one world can be the start, but %s all are", strings.Join(starts, ", ")))
+	}
+
+	// walk from the spawn room through every exit to find the rooms that players can get to
+	spawnWorldID, spawnRoomID := startRoom(worldFiles)
+	if !roomExists(spawnWorldID, spawnRoomID) {
+		v.problem(SeverityError, "spawn-room", "", fmt.Sprintf("the spawn room %d in world '%s' doesn't exist", spawnRoomID, spawnWorldID))
+		return
+	}
+
+	type roomKey struct {
+		worldID string
+		roomID  int
+	}
+	reached := map[roomKey]bool{{spawnWorldID, spawnRoomID}: true}
+	queue := []roomKey{{spawnWorldID, spawnRoomID}}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		room := worlds[key.worldID][key.roomID]
+		var exits []Exit
+		for _, exit := range room.Exits {
+			exits = append(exits, exit)
+		}
+		for _, exit := range room.NamedExits {
+			exits = append(exits, exit)
+		}
+
+		for _, exit := range exits {
+			next := roomKey{exitWorldID(key.worldID, exit), exit.RoomID}
+			if reached[next] || !roomExists(next.worldID, next.roomID) {
+				continue
+			}
+			reached[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	for worldID, rooms := range worlds {
+		for roomID := range rooms {
+			if !reached[roomKey{worldID, roomID}] {
+				v.problem(SeverityWarning, "unreachable-room", roomFiles[worldID][roomID], fmt.Sprintf("room %d in world '%s' can't be reached from the spawn room", roomID, worldID))
+			}
+		}
+	}
+}
+
+// loadErrors notes the files that a loader couldn't load, or that the folder couldn't be read.
+func (v *validator) loadErrors(folder string, err error) {
+	if err == nil {
+		return
+	}
+
+	if badFiles, ok := err.(fileErrors); ok {
+		for filePath, i := range badFiles {
+			v.problem(SeverityError, i.check, filePath, i.message)
+		}
+		return
+	}
+
+	v.problem(SeverityError, "read", folder, err.Error())
+}
+
+func (v *validator) problem(severity, check, filePath, message string) {
+	if rel, err := filepath.Rel(v.dataFolder, filePath); err == nil && filePath != "" {
+		filePath = rel
+	}
+	v.problems = append(v.problems, Problem{
+		Severity: severity,
+		Check:    check,
+		File:     filePath,
+		Message:  message,
+	})
+}
+
+// checkRoom finds the problems with a room that would stop it loading, or leave players stuck.
+// worldExists and roomExists say whether the places that exits lead to are there.
+func checkRoom(worldID string, room *Room, worldExists func(worldID string) bool, roomExists func(worldID string, roomID int) bool) []issue {
+	var issues []issue
+
+	checkExit := func(label string, exit Exit) {
+		if exit.Door != "" {
+			if _, err := model.StringToDoorState(exit.Door); err != nil {
+				issues = append(issues, issue{"door", fmt.Sprintf("the exit %s has an unknown door state '%s'", label, exit.Door)})
+			}
+		}
+
+		toWorldID := exitWorldID(worldID, exit)
+		if !worldExists(toWorldID) {
+			issues = append(issues, issue{"missing-world", fmt.Sprintf("the exit %s leads into world '%s', which doesn't exist", label, toWorldID)})
+			return
+		}
+		if !roomExists(toWorldID, exit.RoomID) {
+			issues = append(issues, issue{"broken-exit", fmt.Sprintf("the exit %s leads to room %d in world '%s', which doesn't exist", label, exit.RoomID, toWorldID)})
+		}
+	}
+
+	for direction, exit := range room.Exits {
+		if _, err := model.StringToDirection(direction); err != nil {
+			issues = append(issues, issue{"direction", fmt.Sprintf("'%s' is not a direction", direction)})
+			continue
+		}
+		checkExit(direction, exit)
+	}
+
+	for keyword, exit := range room.NamedExits {
+		if exit.Door != "" {
+			issues = append(issues, issue{"door", fmt.Sprintf("the named exit '%s' can't have a door, only compass exits can", keyword)})
+		}
+		checkExit("'"+keyword+"'", exit)
+	}
+
+	if room.Script != "" {
+		if _, err := parse.Parse(strings.NewReader(room.Script), room.Name); err != nil {
+			issues = append(issues, issue{"script", fmt.Sprintf("the script doesn't compile: %s", strings.TrimSpace(err.Error()))})
+		}
+	}
+
+	return issues
+}
+
+// exitWorldID is the world an exit leads to, exits with no world stay in the world they are in.
+func exitWorldID(worldID string, exit Exit) string {
+	if exit.WorldID == "" {
+		return worldID
+	}
+	return exit.WorldID
+}
@@ -0,0 +1,193 @@
+package static
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// worldFileName is the name of the optional file in a world folder that describes the world as a whole, in any of the data formats.
+const worldFileName = "world"
+
+// Worlds without a world file that makes them the start world fall back to the old spawn room.
+const (
+	defaultSpawnWorldID = "@spawn"
+	defaultSpawnRoomID  = 1
+)
+
+const (
+	// WorldFlagInstancable worlds are copied for each player or group that enters them, like a ! at the start of the folder name
+	WorldFlagInstancable = "instancable"
+	// WorldFlagAlone worlds don't show players to each other, like an @ at the start of the folder name
+	WorldFlagAlone = "alone"
+	// WorldFlagStart marks the world whose spawn room new characters begin in
+	WorldFlagStart = "start"
+)
+
+type World struct {
+	// Name is shown to players, the folder name is only the world's ID
+	Name  string   `toml:",omitempty"`
+	Flags []string `toml:",omitempty"`
+
+	// SpawnRoom is where characters arrive in the world
+	SpawnRoom int `toml:"spawn_room,omitzero"`
+	// RecallRoom is where characters are sent when their room in the world is gone, it defaults to the spawn room
+	RecallRoom int `toml:"recall_room,omitzero"`
+	// RecallWorld is the world the recall room is in, if it isn't this one, so that instancable worlds can recall to outside their entrance
+	RecallWorld string `toml:"recall_world,omitempty"`
+	// InstanceTimeout is how long an empty instance is kept, such as "30m". Instances are kept forever without one.
+	InstanceTimeout string `toml:"instance_timeout,omitempty"`
+
+	MinLevel int `toml:"min_level,omitzero"`
+	MaxLevel int `toml:"max_level,omitzero"`
+
+	// Region is used by the world's rooms that don't have one of their own
+	Region string `toml:",omitempty"`
+}
+
+// loadWorld reads the world file in the world folder. Worlds don't need one, so an empty world is returned if there isn't one.
+func loadWorld(folder string) (*World, error) {
+	filePath, ok := findNamedFile(folder, worldFileName)
+	if !ok {
+		return &World{}, nil
+	}
+
+	var world World
+	if err := decodeFile(filePath, &world); err != nil {
+		return nil, err
+	}
+
+	return &world, nil
+}
+
+func isWorldFile(name string) bool {
+	return isDataFile(name) && dataFileName(name) == worldFileName
+}
+
+// worldFilePath is the world folder's world file, or where a new one would go.
+func worldFilePath(folder string) string {
+	if filePath, ok := findNamedFile(folder, worldFileName); ok {
+		return filePath
+	}
+	return path.Join(folder, worldFileName+defaultExtension)
+}
+
+// hasFlag checks the world file's flags, and the folder name prefixes that were used before there were world files.
+func (w *World) hasFlag(worldID string, flag string) bool {
+	switch {
+	case flag == WorldFlagInstancable && strings.HasPrefix(worldID, "!"):
+		return true
+	case flag == WorldFlagAlone && strings.HasPrefix(worldID, "@"):
+		return true
+	}
+
+	for _, f := range w.Flags {
+		if strings.EqualFold(f, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *World) instanceTimeout() (time.Duration, error) {
+	if w.InstanceTimeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(w.InstanceTimeout)
+}
+
+// applyTo copies the world file's details on to the simulation world.
+func (w *World) applyTo(world *model.World) error {
+	instanceTimeout, err := w.instanceTimeout()
+	if err != nil {
+		return err
+	}
+
+	world.Name = w.Name
+	world.Instancable = w.hasFlag(string(world.WorldID), WorldFlagInstancable)
+	world.Alone = w.hasFlag(string(world.WorldID), WorldFlagAlone)
+	world.SpawnRoom = model.RoomID(w.SpawnRoom)
+	world.RecallRoom = model.RoomID(w.RecallRoom)
+	world.RecallWorldID = model.WorldID(w.RecallWorld)
+	world.InstanceTimeout = instanceTimeout
+	world.MinLevel = w.MinLevel
+	world.MaxLevel = w.MaxLevel
+
+	for _, room := range world.Rooms {
+		room.Alone = world.Alone
+	}
+
+	return nil
+}
+
+// checkWorld finds the problems with a world file. roomExists says whether the rooms it names are there.
+func checkWorld(worldID string, world *World, roomExists func(worldID string, roomID int) bool) []issue {
+	var issues []issue
+
+	for _, flag := range world.Flags {
+		switch strings.ToLower(flag) {
+		case WorldFlagInstancable, WorldFlagAlone, WorldFlagStart:
+		default:
+			issues = append(issues, issue{"world-flag", fmt.Sprintf("'%s' is not a world flag", flag)})
+		}
+	}
+
+	if world.SpawnRoom != 0 && !roomExists(worldID, world.SpawnRoom) {
+		issues = append(issues, issue{"spawn-room", fmt.Sprintf("the spawn room %d doesn't exist", world.SpawnRoom)})
+	}
+	if world.hasFlag(worldID, WorldFlagStart) {
+		if world.SpawnRoom == 0 {
+			issues = append(issues, issue{"spawn-room", "the start world needs a spawn room"})
+		}
+		if world.hasFlag(worldID, WorldFlagInstancable) {
+			issues = append(issues, issue{"world-flag", "the start world can't be instancable"})
+		}
+	}
+
+	if world.RecallRoom != 0 {
+		recallWorldID := world.RecallWorld
+		if recallWorldID == "" {
+			recallWorldID = worldID
+		}
+		if !roomExists(recallWorldID, world.RecallRoom) {
+			issues = append(issues, issue{"recall-room", fmt.Sprintf("the recall room %d in world '%s' doesn't exist", world.RecallRoom, recallWorldID)})
+		}
+	} else if world.RecallWorld != "" {
+		issues = append(issues, issue{"recall-room", "there is a recall world but no recall room"})
+	}
+
+	if _, err := world.instanceTimeout(); err != nil {
+		issues = append(issues, issue{"instance-timeout", fmt.Sprintf("'%s' is not a duration", world.InstanceTimeout)})
+	}
+
+	if world.MinLevel < 0 || world.MaxLevel < 0 || (world.MaxLevel != 0 && world.MinLevel > world.MaxLevel) {
+		issues = append(issues, issue{"level-range", fmt.Sprintf("%d to %d is not a level range", world.MinLevel, world.MaxLevel)})
+	}
+
+	return issues
+}
+
+// startRoom finds the spawn room of the start world.
+// Data folders from before world files had an @spawn world, so that is used if no world is marked as the start.
+func startRoom(worlds map[string]*World) (string, int) {
+	for worldID, world := range worlds {
+		if world.hasFlag(worldID, WorldFlagStart) {
+			return worldID, world.SpawnRoom
+		}
+	}
+	return defaultSpawnWorldID, defaultSpawnRoomID
+}
+
+// startWorlds lists the worlds marked as the start, there should only be one.
+func startWorlds(worlds map[string]*World) []string {
+	var ids []string
+	for worldID, world := range worlds {
+		if world.hasFlag(worldID, WorldFlagStart) {
+			ids = append(ids, worldID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,48 @@
+package simulation
+
+import (
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// SetDoorState changes the door in the given exit of the room, and mirrors the change on to the door on the other side.
+// It is used by room scripts.
+func (s *Simulation) SetDoorState(room *model.Room, direction model.Direction, state model.DoorState) error {
+	exit := room.Exits[direction]
+	if exit == nil || exit.Door == nil {
+		return model.ErrNoDoor
+	}
+
+	s.setDoorState(room, direction, state)
+
+	return nil
+}
+
+// setDoorState assumes that the door exists.
+// Characters on the other side of the door are told that it has changed.
+func (s *Simulation) setDoorState(room *model.Room, direction model.Direction, state model.DoorState) {
+	exit := room.Exits[direction]
+	exit.Door.State = state
+
+	otherRoom, otherDirection, otherExit := s.findPairedExit(room, exit)
+	if otherExit == nil || otherExit.Door == nil {
+		return
+	}
+
+	otherExit.Door.State = state
+	otherRoom.Dispatch(model.EvtDoorChanges{Direction: otherDirection, State: state})
+}
+
+// findPairedExit looks in the room the exit leads to for a compass exit that comes back again.
+func (s *Simulation) findPairedExit(room *model.Room, exit *model.Exit) (*model.Room, model.Direction, *model.Exit) {
+	otherRoom, err := s.GetRoom(exit.WorldID, exit.RoomID)
+	if err != nil {
+		return nil, 0, nil
+	}
+
+	direction, keyword, otherExit := otherRoom.ExitLeadingTo(room.WorldID, room.ID)
+	if otherExit == nil || keyword != "" {
+		return nil, 0, nil
+	}
+
+	return otherRoom, direction, otherExit
+}
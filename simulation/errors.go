@@ -9,6 +9,18 @@ var (
 	ErrCharacterAwake = errors.New("character is awake")
 	// ErrCharacterAsleep is thrown when trying do anything other than wake up a sleeping character
 	ErrCharacterAsleep = errors.New("character is asleep")
+	// ErrSessionEnded is thrown when a connection acts for a character that has since been taken over by another
+	ErrSessionEnded = errors.New("the session has ended")
+	// ErrCharacterNameLength means that a new character's name is too short or too long
+	ErrCharacterNameLength = errors.New("names must be between 3 and 16 letters long")
+	// ErrCharacterNameInvalid means that a new character's name has something other than letters in it
+	ErrCharacterNameInvalid = errors.New("names can only contain the letters a to z")
+	// ErrCharacterNameReserved means that a new character's name could be mistaken for a command or target
+	ErrCharacterNameReserved = errors.New("that name is reserved")
+	// ErrCharacterNameTaken means that a new character's name is already used by another character
+	ErrCharacterNameTaken = errors.New("that name is already taken")
+	// ErrBackgroundNotFound means that a background was referred to that has not been loaded
+	ErrBackgroundNotFound = errors.New("background not found")
 	// ErrRoomNotFound means that an attempt was made to act on a room that does not exist
 	ErrRoomNotFound = errors.New("room not found")
 	// ErrWorldNotFound means that an attempt was made to act on a world that does not exist
@@ -17,6 +29,21 @@ var (
 	ErrContainerNotFound = errors.New("container not found")
 	// ErrItemNotFound means that an attempt was made to act on an item that is not available to the character
 	ErrItemNotFound = errors.New("item not found")
+	// ErrItemDefinitionNotFound means that an item definition was referred to that has not been loaded
+	ErrItemDefinitionNotFound = errors.New("item definition not found")
+	// ErrItemDefinitionInUse means that an item definition can't be removed because there are items of it in the world,
+	// or shops, recipes, quests or backgrounds that refer to it
+	ErrItemDefinitionInUse = errors.New("item definition is still in use")
+	// ErrExitNotFound means that a character tried to use an exit that is not in their room
+	ErrExitNotFound = errors.New("exit not found")
+	// ErrRecipeNotFound means that a character tried to craft something there is no recipe for
+	ErrRecipeNotFound = errors.New("recipe not found")
+	// ErrSocialNotFound means that a character tried to perform a social that has not been loaded
+	ErrSocialNotFound = errors.New("social not found")
+	// ErrQuestNotFound means that a quest was referred to that has not been loaded
+	ErrQuestNotFound = errors.New("quest not found")
+	// ErrQuestNotStarted means that a script tried to move a character through a quest they do not have
+	ErrQuestNotStarted = errors.New("quest not started")
 	// ErrCannotEquipItem means that a character attempted to equip an item that is not equipable
 	ErrCannotEquipItem = errors.New("cannot equip item")
 )
@@ -0,0 +1,199 @@
+package simulation
+
+import (
+	"strings"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+func (s *Simulation) follow(actor *model.Character, c model.CommandFollow) {
+	target := c.Target
+	if target == "" || target == "me" || target == "self" || strings.EqualFold(target, actor.Name) {
+		s.stopFollowing(actor)
+		return
+	}
+
+	leader := actor.Room.FindCharacter(target)
+	if leader == nil {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: target})
+		return
+	}
+
+	if actor.Following != nil {
+		s.stopFollowing(actor)
+	}
+
+	actor.Following = leader
+	s.dispatchToRoom(actor, model.EvtCharacterFollows{Character: actor, Target: leader})
+}
+
+func (s *Simulation) stopFollowing(actor *model.Character) {
+	if actor.Following == nil {
+		actor.Dispatch(model.EvtNotFollowing{})
+		return
+	}
+
+	leader := actor.Following
+	actor.Following = nil
+	s.dispatchToRoom(actor, model.EvtCharacterStopsFollowing{Character: actor, Target: leader})
+}
+
+// followers returns the awake characters in the room that are following the leader.
+func followers(room *model.Room, leader *model.Character) []*model.Character {
+	var result []*model.Character
+	for _, ch := range room.Characters {
+		if ch.Awake && ch.Following == leader {
+			result = append(result, ch)
+		}
+	}
+	return result
+}
+
+// inviteToGroup creates a group led by the actor if they aren't in one already.
+// Only the leader can invite, and the target can be anywhere in the world.
+func (s *Simulation) inviteToGroup(actor *model.Character, c model.CommandGroupInvite) {
+	target := s.findAwakeCharacterByName(c.Target)
+	if target == nil || target == actor {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	if actor.Group != nil && actor.Group.Leader != actor {
+		actor.Dispatch(model.EvtNotGroupLeader{})
+		return
+	}
+
+	if target.Group != nil {
+		actor.Dispatch(model.EvtAlreadyInGroup{Character: target})
+		return
+	}
+
+	if actor.Group == nil {
+		actor.Group = model.NewGroup(actor)
+		s.groups[actor.Group.ID] = actor.Group
+	}
+
+	actor.Group.Invite(target)
+
+	evt := model.EvtGroupInvite{Group: actor.Group, Character: actor, Target: target}
+	actor.Dispatch(evt)
+	target.Dispatch(evt)
+}
+
+func (s *Simulation) acceptGroupInvite(actor *model.Character, c model.CommandGroupAccept) {
+	if actor.Group != nil {
+		actor.Dispatch(model.EvtAlreadyInGroup{Character: actor})
+		return
+	}
+
+	var group *model.Group
+	for _, g := range s.groups {
+		if !g.Invited(actor) {
+			continue
+		}
+		if c.Inviter == "" || strings.EqualFold(g.Leader.Name, c.Inviter) {
+			group = g
+			break
+		}
+	}
+
+	if group == nil {
+		actor.Dispatch(model.EvtNoGroupInvite{})
+		return
+	}
+
+	group.Add(actor)
+	actor.Group = group
+	actor.Following = group.Leader
+
+	s.dispatchToGroup(group, model.EvtGroupJoined{Group: group, Character: actor})
+}
+
+func (s *Simulation) leaveGroup(actor *model.Character, c model.CommandGroupLeave) {
+	if actor.Group == nil {
+		actor.Dispatch(model.EvtNotInGroup{})
+		return
+	}
+
+	s.removeFromGroup(actor, false)
+}
+
+func (s *Simulation) kickFromGroup(actor *model.Character, c model.CommandGroupKick) {
+	if actor.Group == nil {
+		actor.Dispatch(model.EvtNotInGroup{})
+		return
+	}
+
+	if actor.Group.Leader != actor {
+		actor.Dispatch(model.EvtNotGroupLeader{})
+		return
+	}
+
+	target := actor.Group.FindMember(c.Target)
+	if target == nil || target == actor {
+		actor.Dispatch(model.EvtCharacterNotFound{Name: c.Target})
+		return
+	}
+
+	s.removeFromGroup(target, true)
+}
+
+// removeFromGroup takes the character out of their group and stops them following the leader.
+// A group left with only one member is disbanded.
+func (s *Simulation) removeFromGroup(character *model.Character, kicked bool) {
+	group := character.Group
+	oldLeader := group.Leader
+
+	group.Remove(character)
+	character.Group = nil
+	if character.Following == oldLeader {
+		character.Following = nil
+	}
+
+	evt := model.EvtGroupLeft{Group: group, Character: character, Kicked: kicked}
+	if group.Leader != oldLeader {
+		evt.NewLeader = group.Leader
+		for _, member := range group.Members {
+			if member.Following == oldLeader {
+				member.Following = group.Leader
+			}
+		}
+	}
+
+	character.Dispatch(evt)
+	s.dispatchToGroup(group, evt)
+
+	if len(group.Members) <= 1 {
+		for _, member := range group.Members {
+			member.Group = nil
+			if member.Following == group.Leader {
+				member.Following = nil
+			}
+		}
+		delete(s.groups, group.ID)
+	}
+}
+
+func (s *Simulation) partyChat(actor *model.Character, c model.CommandPartyChat) {
+	if actor.Group == nil {
+		actor.Dispatch(model.EvtNotInGroup{})
+		return
+	}
+
+	s.dispatchToGroup(actor.Group, model.EvtPartyChat{Character: actor, Content: c.Content})
+}
+
+func (s *Simulation) dispatchToGroup(group *model.Group, event interface{}) {
+	for _, member := range group.Members {
+		member.Dispatch(event)
+	}
+}
+
+func (s *Simulation) findAwakeCharacterByName(name string) *model.Character {
+	for _, ch := range s.characters {
+		if ch.Awake && strings.EqualFold(ch.Name, name) {
+			return ch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// destinationRoom finds the room an exit leads to.
+// Exits into instancable worlds lead into the copy of that world for the instance key.
+func (s *Simulation) destinationRoom(exit *model.Exit, key string) (*model.Room, error) {
+	world, ok := s.worlds[exit.WorldID]
+	if !ok {
+		return nil, ErrWorldNotFound
+	}
+
+	if world.Instancable && !world.Instance {
+		world = s.instanceWorld(world, key)
+	}
+
+	room, ok := world.Rooms[exit.RoomID]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	return room, nil
+}
+
+// instanceKey decides which instances the character shares, their own or their group's.
+func instanceKey(c *model.Character) string {
+	if c.Group != nil {
+		return string(c.Group.ID)
+	}
+	return string(c.ID)
+}
+
+func instanceID(template model.WorldID, key string) model.WorldID {
+	return model.WorldID(fmt.Sprintf("%s#%s", template, key))
+}
+
+// instanceWorld returns the instance of the template world for the key, copying the template if there isn't one yet.
+// Exits between rooms in the template lead between the rooms of the instance instead.
+func (s *Simulation) instanceWorld(template *model.World, key string) *model.World {
+	id := instanceID(template.WorldID, key)
+	if world, ok := s.worlds[id]; ok {
+		return world
+	}
+
+	world := model.NewWorld(id, false, true, template.Alone)
+	world.Name = template.Name
+	world.Template = template.WorldID
+	world.SpawnRoom = template.SpawnRoom
+	world.MinLevel = template.MinLevel
+	world.MaxLevel = template.MaxLevel
+	s.worlds[id] = world
+
+	for roomID, t := range template.Rooms {
+		room, _ := s.CreateRoom(id, roomID, t.Name, t.Region, t.Description, t.Script())
+		room.Extras = t.Extras
+		room.ShopID = t.ShopID
+		room.Tags = t.Tags
+
+		for direction, exit := range t.Exits {
+			if exit != nil {
+				room.Exits[direction] = copyExit(exit, template.WorldID, id)
+			}
+		}
+		for keyword, exit := range t.NamedExits {
+			room.NamedExits[keyword] = copyExit(exit, template.WorldID, id)
+		}
+	}
+
+	return world
+}
+
+func copyExit(exit *model.Exit, from, to model.WorldID) *model.Exit {
+	e := *exit
+	if e.WorldID == from {
+		e.WorldID = to
+	}
+	if exit.Door != nil {
+		e.Door = model.NewDoor(exit.Door.State, exit.Door.KeyID)
+	}
+	return &e
+}
+
+// expireInstances throws away instances that have had no one awake in them for longer than their world's instance timeout.
+// Characters that fell asleep inside are moved to the recall room, so that they wake up somewhere that still exists.
+func (s *Simulation) expireInstances(now time.Time) {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	for id, world := range s.worlds {
+		if !world.Instance {
+			continue
+		}
+
+		template, ok := s.worlds[world.Template]
+		if !ok || template.InstanceTimeout == 0 {
+			continue
+		}
+
+		if instanceOccupied(world) {
+			world.EmptySince = time.Time{}
+			continue
+		}
+		if world.EmptySince.IsZero() {
+			world.EmptySince = now
+			continue
+		}
+		if now.Sub(world.EmptySince) < template.InstanceTimeout {
+			continue
+		}
+
+		recall := s.recallRoom(id)
+		if recall == nil {
+			continue
+		}
+
+		for _, room := range world.Rooms {
+			for _, character := range append([]*model.Character(nil), room.Characters...) {
+				room.RemoveCharacter(character)
+				character.Room = recall
+				recall.AddCharacter(character)
+			}
+			delete(s.containers, room.Container.ID())
+		}
+		delete(s.worlds, id)
+	}
+}
+
+func instanceOccupied(world *model.World) bool {
+	for _, room := range world.Rooms {
+		for _, character := range room.Characters {
+			if character.Awake {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recallRoom finds somewhere to put a character whose room in the world is gone.
+// Instances use their template world's recall room, and anything without one uses the spawn room.
+func (s *Simulation) recallRoom(worldID model.WorldID) *model.Room {
+	// instance IDs are the template's ID followed by the instance key
+	templateID, _, _ := strings.Cut(string(worldID), "#")
+
+	world, ok := s.worlds[model.WorldID(templateID)]
+	if !ok {
+		return s.spawnRoom
+	}
+
+	if world.RecallRoom != 0 {
+		recallWorldID := world.RecallWorldID
+		if recallWorldID == "" {
+			recallWorldID = world.WorldID
+		}
+		// rooms in the template of an instancable world are never entered themselves
+		if recallWorld, ok := s.worlds[recallWorldID]; ok && !recallWorld.Instancable {
+			if room, ok := recallWorld.Rooms[world.RecallRoom]; ok {
+				return room
+			}
+		}
+	}
+
+	if !world.Instancable {
+		if room, ok := world.Rooms[world.SpawnRoom]; ok {
+			return room
+		}
+	}
+
+	return s.spawnRoom
+}
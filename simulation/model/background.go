@@ -0,0 +1,17 @@
+package model
+
+type BackgroundID int64
+
+// Background is a history players choose for their character when creating it, which decides what they start with.
+type Background struct {
+	ID          BackgroundID
+	Name        string
+	Description string
+	Money       int64
+	Kit         []BackgroundItem
+}
+
+type BackgroundItem struct {
+	Definition *ItemDefinition
+	Quantity   int
+}
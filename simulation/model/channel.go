@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// channelHistoryLength is how many messages a channel remembers to show characters that join it.
+const channelHistoryLength = 20
+
+// Channel is a named chat channel that reaches every character who has joined it, wherever they are.
+// Membership is kept on the characters, the channel only keeps its recent history.
+type Channel struct {
+	Name    string
+	History []ChannelMessage
+}
+
+type ChannelMessage struct {
+	Character *Character
+	Content   string
+	Time      time.Time
+}
+
+func NewChannel(name string) *Channel {
+	return &Channel{
+		Name: name,
+	}
+}
+
+// Record adds a message to the channel's history, forgetting the oldest message if it is full.
+func (ch *Channel) Record(message ChannelMessage) {
+	ch.History = append(ch.History, message)
+	if len(ch.History) > channelHistoryLength {
+		ch.History = ch.History[len(ch.History)-channelHistoryLength:]
+	}
+}
+
+// InChannel returns true if the character has joined the channel.
+func (c *Character) InChannel(name string) bool {
+	for _, channel := range c.Channels {
+		if channel == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -15,10 +17,44 @@ type Character struct {
 	Awake     bool
 	Room      *Room
 	Container Container
+	Money     int64 // wallet balance
+	Quests    map[QuestID]*QuestProgress
+	Flags     map[string]bool // set by scripts
+	Following *Character      // moves with this character, nil if not following anyone
+	Group     *Group          // nil if not in a group
+	Channels  []string        // names of the chat channels the character has joined
+	ReplyTo   *Character      // the last character to send this one a tell
 	Commands  chan interface{}
 	Events    chan interface{}
+
+	// Description is how the character describes themself to others that look at them
+	Description string
+	Attributes  Attributes
+	Background  BackgroundID
+
+	// Session identifies the connection currently playing the character
+	Session string
+	// Linkdead characters have lost their connection but stay in the world for a while in case they come back
+	Linkdead      bool
+	LinkdeadSince time.Time
+	missed        []interface{}
+
+	// done is closed when the session ends, so that a connection waiting to send a command stops waiting.
+	// Commands is never closed, as connections can still be sending to it.
+	done chan struct{}
+}
+
+// Attributes are a character's natural abilities.
+type Attributes struct {
+	Strength     int
+	Dexterity    int
+	Constitution int
+	Intelligence int
 }
 
+// maxMissedEvents is how many events are kept for a linkdead character to catch up on.
+const maxMissedEvents = 100
+
 // NewCharacter is a helper function for creating a new character in the simulation.
 // It requires the character's name and the room to @spawn the character in.
 func NewCharacter(name string, room *Room) *Character {
@@ -28,29 +64,95 @@ func NewCharacter(name string, room *Room) *Character {
 		Room:      room,
 		Rig:       &Rig{},
 		Container: NewCharacterContainer(),
+		Quests:    make(map[QuestID]*QuestProgress),
+		Flags:     make(map[string]bool),
 	}
 }
 
 // WakeUp initializes a buffered channel of simulation events that happen to the character.
 // It also wake it up, which allows the player to control it.
-func (c *Character) WakeUp() {
+func (c *Character) WakeUp(session string) {
 	c.Commands = make(chan interface{}, 1)
 	c.Events = make(chan interface{}, 10)
 	c.Awake = true
+	c.Session = session
+	c.done = make(chan struct{})
 }
 
 // Sleep closes the channel of simulation events for this character and puts the character to sleep.
 func (c *Character) Sleep() {
 	c.Awake = false
+	if !c.Linkdead {
+		close(c.Events)
+	}
+	close(c.done)
+	c.Linkdead = false
+	c.missed = nil
+}
+
+// Disconnect closes the channel of simulation events and marks the character as linkdead.
+// Events are kept until the character reconnects or falls asleep.
+func (c *Character) Disconnect(now time.Time) {
+	close(c.Events)
+	c.Linkdead = true
+	c.LinkdeadSince = now
+}
+
+// Reconnect gives a linkdead character a new channel of simulation events with everything they missed already in it.
+func (c *Character) Reconnect(session string) {
+	c.Events = make(chan interface{}, len(c.missed)+10)
+	for _, event := range c.missed {
+		c.Events <- event
+	}
+	c.missed = nil
+	c.Linkdead = false
+	c.startSession(session)
+}
+
+// TakeOver hands an awake character to a new session. The old session is sent the event and its channel is closed.
+func (c *Character) TakeOver(session string, event interface{}) {
+	// don't wait on an old session that has stopped reading, make room for the event by dropping the oldest instead
+	for sent := false; !sent; {
+		select {
+		case c.Events <- event:
+			sent = true
+		default:
+			select {
+			case <-c.Events:
+			default:
+			}
+		}
+	}
 	close(c.Events)
-	close(c.Commands)
+	c.Events = make(chan interface{}, 10)
+	c.startSession(session)
+}
+
+// startSession ends the current session and hands the character to a new one.
+func (c *Character) startSession(session string) {
+	close(c.done)
+	c.done = make(chan struct{})
+	c.Session = session
+}
+
+// Done is closed when the current session ends, by the character falling asleep or being taken over.
+func (c *Character) Done() <-chan struct{} {
+	return c.done
 }
 
 // Dispatch is used by the simulation to send events to the character's event stream.
+// Linkdead characters keep hold of their most recent events instead.
 func (c *Character) Dispatch(event interface{}) {
 	if !c.Awake {
 		return
 	}
+	if c.Linkdead {
+		c.missed = append(c.missed, event)
+		if len(c.missed) > maxMissedEvents {
+			c.missed = c.missed[len(c.missed)-maxMissedEvents:]
+		}
+		return
+	}
 	c.Events <- event
 }
 
@@ -71,3 +173,49 @@ func (c *Character) SearchInventory(alias string) *Item {
 	}
 	return nil
 }
+
+// HasItemDefinition returns true if the character is carrying an item of the given definition,
+// either in their inventory, equipped on their rig or stored inside something they carry.
+func (c *Character) HasItemDefinition(id ItemDefinitionID) bool {
+	items := c.Rig.Items()
+	for _, item := range c.Container.Items() {
+		items = append(items, item)
+	}
+
+	for len(items) > 0 {
+		item := items[0]
+		items = items[1:]
+
+		if item.Definition.ID == id {
+			return true
+		}
+
+		if item.Container != nil {
+			for _, child := range item.Container.Items() {
+				items = append(items, child)
+			}
+		}
+	}
+
+	return false
+}
+
+// Containers returns every container the character can reach into: their inventory,
+// anything that can hold items in their inventory and anything that can hold items on their rig.
+func (c *Character) Containers() []Container {
+	containers := []Container{c.Container}
+
+	for _, item := range c.Container.Items() {
+		if item.Container != nil {
+			containers = append(containers, item.Container)
+		}
+	}
+
+	for _, item := range c.Rig.Items() {
+		if item.Container != nil {
+			containers = append(containers, item.Container)
+		}
+	}
+
+	return containers
+}
@@ -1,19 +1,31 @@
 package model
 
+import "time"
+
+// CommandMove moves the character through an exit.
+// If Keyword is set the named exit is used, otherwise the compass exit in Direction.
 type CommandMove struct {
 	Direction Direction
+	Keyword   string
+}
+
+type CommandSearch struct {
 }
 
 type CommandSay struct {
 	Content string
 }
 
+// CommandTake picks an item up. A Quantity of 0 takes the whole stack.
 type CommandTake struct {
-	Item *Item
+	Item     *Item
+	Quantity int
 }
 
+// CommandDrop puts an item down. A Quantity of 0 drops the whole stack.
 type CommandDrop struct {
-	Item *Item
+	Item     *Item
+	Quantity int
 }
 
 type CommandEquip struct {
@@ -24,3 +36,203 @@ type CommandEquip struct {
 type CommandUnequip struct {
 	Item *Item
 }
+
+type CommandOpen struct {
+	Direction Direction
+}
+
+type CommandClose struct {
+	Direction Direction
+}
+
+type CommandLock struct {
+	Direction Direction
+}
+
+type CommandUnlock struct {
+	Direction Direction
+}
+
+// CommandBuy buys items from the shop in the character's room. A Quantity of 0 buys one.
+type CommandBuy struct {
+	Alias    string
+	Quantity int
+}
+
+// CommandSell sells an item to the shop in the character's room. A Quantity of 0 sells the whole stack.
+type CommandSell struct {
+	Item     *Item
+	Quantity int
+}
+
+type CommandCraft struct {
+	Recipe *Recipe
+}
+
+type CommandTalk struct {
+	Target string
+}
+
+// CommandFollow starts following the named character in the room. An empty Target stops following.
+type CommandFollow struct {
+	Target string
+}
+
+type CommandGroupInvite struct {
+	Target string
+}
+
+// CommandGroupAccept joins the group of the character that sent the invite. An empty Inviter accepts any invite.
+type CommandGroupAccept struct {
+	Inviter string
+}
+
+type CommandGroupLeave struct {
+}
+
+type CommandGroupKick struct {
+	Target string
+}
+
+type CommandPartyChat struct {
+	Content string
+}
+
+// CommandTell sends a message to a character anywhere in the world.
+type CommandTell struct {
+	Target  string
+	Content string
+}
+
+// CommandReply sends a tell to whoever last sent the character one.
+type CommandReply struct {
+	Content string
+}
+
+// CommandWhisper sends a message to a character in the same room that nobody else hears.
+type CommandWhisper struct {
+	Target  string
+	Content string
+}
+
+// CommandShout sends a message to everyone in the same region of the world.
+type CommandShout struct {
+	Content string
+}
+
+type CommandEmote struct {
+	Content string
+}
+
+type CommandChannelJoin struct {
+	Channel string
+}
+
+type CommandChannelLeave struct {
+	Channel string
+}
+
+type CommandChannelSay struct {
+	Channel string
+	Content string
+}
+
+// CommandSocial performs a social, aimed at the named character in the room if Target is set.
+type CommandSocial struct {
+	Social *Social
+	Target string
+}
+
+// CommandAdminGoto moves the admin to the named character, or to the room if Target is empty.
+// An empty WorldID means the admin's current world.
+type CommandAdminGoto struct {
+	Target  string
+	WorldID WorldID
+	RoomID  RoomID
+}
+
+// CommandAdminSummon brings the named character to the admin's room.
+type CommandAdminSummon struct {
+	Target string
+}
+
+// CommandAdminTeleport moves the named character to the room.
+type CommandAdminTeleport struct {
+	Target  string
+	WorldID WorldID
+	RoomID  RoomID
+}
+
+// CommandAdminKick disconnects the named character's session and puts them to sleep.
+type CommandAdminKick struct {
+	Target string
+}
+
+// CommandAdminInspect shows everything about the admin's room, a character or an item the admin can see.
+// A Target of "here" is the room.
+type CommandAdminInspect struct {
+	Target string
+}
+
+// CommandAdminSet changes a field on the named character.
+type CommandAdminSet struct {
+	Target string
+	Field  string
+	Value  string
+}
+
+// CommandAdminShutdown stops the server after the delay, warning everyone as it gets closer.
+type CommandAdminShutdown struct {
+	Delay time.Duration
+}
+
+type CommandAdminShutdownCancel struct {
+}
+
+// CommandAdminPurge destroys every item on the floor of the admin's room.
+type CommandAdminPurge struct {
+}
+
+// CommandBuildDig makes a new room through an exit from the builder's room, with an exit back again.
+type CommandBuildDig struct {
+	Direction Direction
+	Name      string
+}
+
+type CommandBuildRename struct {
+	Name string
+}
+
+type CommandBuildDescribe struct {
+	Description string
+}
+
+type CommandBuildRegion struct {
+	Region string
+}
+
+// CommandBuildExitLink adds an exit from the builder's room. An empty WorldID means the room's own world.
+type CommandBuildExitLink struct {
+	Direction Direction
+	WorldID   WorldID
+	RoomID    RoomID
+}
+
+type CommandBuildExitUnlink struct {
+	Direction Direction
+}
+
+type CommandBuildItemDefinitionCreate struct {
+	Name string
+}
+
+// CommandBuildReload loads changes to the data folder without waiting for them to be noticed.
+type CommandBuildReload struct {
+}
+
+// CommandBuildItemDefinitionEdit changes one field of an item definition, using the field names from the item files.
+type CommandBuildItemDefinitionEdit struct {
+	ID    ItemDefinitionID
+	Field string
+	Value string
+}
@@ -27,6 +27,17 @@ func (c *BaseContainer) Items() map[ItemID]*Item {
 	return c.items
 }
 
+// put adds the item to the container, merging it into a matching stack if there is one.
+func (c *BaseContainer) put(item *Item) {
+	for _, existing := range c.items {
+		if existing.Stacks(item) {
+			existing.Quantity += item.Quantity
+			return
+		}
+	}
+	c.items[item.ID] = item
+}
+
 // RoomContainer represents the floor of rooms items are dropped on to
 type RoomContainer struct {
 	BaseContainer
@@ -42,7 +53,7 @@ func NewRoomContainer() Container {
 }
 
 func (c *RoomContainer) PutItem(item *Item) {
-	c.items[item.ID] = item
+	c.put(item)
 }
 
 func (c *RoomContainer) RemoveItem(itemID ItemID) {
@@ -65,7 +76,7 @@ func NewItemContainer() Container {
 
 func (c *ItemContainer) PutItem(item *Item) {
 	// check for capacity and stuff
-	c.items[item.ID] = item
+	c.put(item)
 }
 
 func (c *ItemContainer) RemoveItem(itemID ItemID) {
@@ -88,7 +99,7 @@ func NewCharacterContainer() Container {
 
 func (c *CharacterContainer) PutItem(item *Item) {
 	// check for capacity and stuff
-	c.items[item.ID] = item
+	c.put(item)
 }
 
 func (c *CharacterContainer) RemoveItem(itemID ItemID) {
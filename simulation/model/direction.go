@@ -18,6 +18,20 @@ const (
 // Direction is an enum of 8-point compass directions.
 type Direction byte
 
+// Directions is every direction, in the order they should be displayed.
+var Directions = []Direction{
+	DirectionNorth,
+	DirectionNorthEast,
+	DirectionEast,
+	DirectionSouthEast,
+	DirectionSouth,
+	DirectionSouthWest,
+	DirectionWest,
+	DirectionNorthWest,
+	DirectionUp,
+	DirectionDown,
+}
+
 // Opposite returns the direction that faces the other way. North would give south.
 func (d Direction) Opposite() Direction {
 	return (d + 5) % 10
@@ -8,4 +8,7 @@ var (
 
 	// ErrItemNotInRig is returned when a character attempts to remove an item from a rig slot but no item with that alias can be found.
 	ErrItemNotInRig = errors.New("item is not in rig")
+
+	// ErrNoDoor is returned when trying to change a door in an exit that does not have one.
+	ErrNoDoor = errors.New("there is no door there")
 )
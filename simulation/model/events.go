@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type EvtCharacterWakesUp struct {
 	Character *Character
 }
@@ -8,6 +10,20 @@ type EvtCharacterFallsAsleep struct {
 	Character *Character
 }
 
+// EvtCharacterLinkdead is sent to the room when a character loses their connection.
+type EvtCharacterLinkdead struct {
+	Character *Character
+}
+
+// EvtCharacterReconnects is sent to the room when a linkdead character comes back.
+type EvtCharacterReconnects struct {
+	Character *Character
+}
+
+// EvtSessionTakenOver is the last event sent to a session when the character is played from somewhere else.
+type EvtSessionTakenOver struct {
+}
+
 type EvtNarration struct {
 	Content string
 }
@@ -21,6 +37,68 @@ type EvtCharacterSpeaks struct {
 	Content   string
 }
 
+// EvtTell is sent to both the character sending the tell and the target.
+type EvtTell struct {
+	Character *Character
+	Target    *Character
+	Content   string
+}
+
+// EvtWhisper is sent to both the character whispering and the target.
+type EvtWhisper struct {
+	Character *Character
+	Target    *Character
+	Content   string
+}
+
+type EvtShout struct {
+	Character *Character
+	Content   string
+}
+
+type EvtEmote struct {
+	Character *Character
+	Content   string
+}
+
+// EvtSocial is sent to the room when a character performs a social. Target is nil if the social isn't aimed at anyone.
+type EvtSocial struct {
+	Social    *Social
+	Character *Character
+	Target    *Character
+}
+
+type EvtNobodyToReplyTo struct {
+}
+
+type EvtChannelMessage struct {
+	Channel   *Channel
+	Character *Character
+	Content   string
+}
+
+// EvtChannelJoined is sent to a character joining a channel, with the channel's recent messages.
+type EvtChannelJoined struct {
+	Channel *Channel
+	History []ChannelMessage
+}
+
+type EvtChannelLeft struct {
+	Channel string
+}
+
+type EvtNotInChannel struct {
+	Channel string
+}
+
+type EvtAlreadyInChannel struct {
+	Channel string
+}
+
+type EvtChannelList struct {
+	Channels []string
+}
+
 type EvtCharacterTakesItem struct {
 	Character *Character
 	Item      *Item
@@ -41,6 +119,10 @@ type EvtCharacterUnequipsItem struct {
 	Item      *Item
 }
 
+type EvtItemNotEquipable struct {
+	Item *Item
+}
+
 type EvtYouAreNotWearing struct {
 	Alias string
 }
@@ -49,14 +131,56 @@ type EvtItemPutIntoStorage struct {
 	Item *Item
 }
 
+// EvtCharacterLeaves is sent when a character leaves the room.
+// If Named is set the character used a named exit and Via is the exit's name.
+// Teleported characters were moved by an admin and didn't use an exit at all.
 type EvtCharacterLeaves struct {
-	Character *Character
-	Direction Direction
+	Character  *Character
+	Direction  Direction
+	Named      bool
+	Via        string
+	Teleported bool
 }
 
+// EvtCharacterArrives is sent when a character arrives in the room.
+// If Named is set the character did not arrive through a compass exit and Via, if not empty, is the name of the exit they came through.
+// Teleported characters were moved by an admin and didn't use an exit at all.
 type EvtCharacterArrives struct {
+	Character  *Character
+	Direction  Direction
+	Named      bool
+	Via        string
+	Teleported bool
+}
+
+// EvtExitRevealed is sent when a hidden exit is found. Character is nil if a script revealed it.
+type EvtExitRevealed struct {
 	Character *Character
-	Direction Direction
+	Label     string
+}
+
+type EvtYouFindNothing struct {
+}
+
+// EvtExitRefuses is sent when an exit's condition stops a character going through it.
+type EvtExitRefuses struct {
+	Message string
+}
+
+type EvtExtraDescription struct {
+	Extra *ExtraDescription
+}
+
+type EvtItemDescription struct {
+	Item *Item
+}
+
+type EvtCharacterDescription struct {
+	Character *Character
+}
+
+type EvtNothingToLookAt struct {
+	Target string
 }
 
 type EvtInventoryDescription struct {
@@ -68,9 +192,302 @@ type EvtAdminSpawnsItem struct {
 	Item      *Item
 }
 
+// EvtAdminTeleports is sent to an admin that moved a character, and to the character if it wasn't the admin.
+type EvtAdminTeleports struct {
+	Admin     *Character
+	Character *Character
+	Room      *Room
+}
+
+type EvtAdminRoomNotFound struct {
+	WorldID WorldID
+	RoomID  RoomID
+}
+
+// EvtAdminKicks is sent to the admin and the character they kicked, whose connection is closed.
+type EvtAdminKicks struct {
+	Admin     *Character
+	Character *Character
+}
+
+// EvtAdminCannotKick is sent when the character's user has a role that the admin's role doesn't outrank.
+type EvtAdminCannotKick struct {
+	Character *Character
+}
+
+// EvtAdminTargetNotFound is sent when there is no room, character or item that matches what the admin asked for.
+type EvtAdminTargetNotFound struct {
+	Target string
+}
+
+type EvtAdminInspectCharacter struct {
+	Character *Character
+}
+
+type EvtAdminInspectRoom struct {
+	Room  *Room
+	World *World
+}
+
+type EvtAdminInspectItem struct {
+	Item *Item
+}
+
+// EvtAdminSetsField is sent to the admin and the character whose field they changed.
+type EvtAdminSetsField struct {
+	Admin     *Character
+	Character *Character
+	Field     string
+	Value     string
+}
+
+type EvtAdminSetFailed struct {
+	Field  string
+	Reason string
+}
+
+// EvtShutdownScheduled is sent to everyone when an admin schedules a shutdown.
+type EvtShutdownScheduled struct {
+	Character *Character
+	In        time.Duration
+}
+
+// EvtShutdownCountdown reminds everyone how long is left before a scheduled shutdown.
+type EvtShutdownCountdown struct {
+	In time.Duration
+}
+
+type EvtShutdownCancelled struct {
+	Character *Character
+}
+
+type EvtNoShutdownScheduled struct {
+}
+
+// EvtServerShuttingDown is the last event sent before the server stops, the connection is closed after it.
+type EvtServerShuttingDown struct {
+}
+
+type EvtAdminPurgesRoom struct {
+	Character *Character
+	Count     int
+}
+
+type EvtCharacterOpensDoor struct {
+	Character *Character
+	Direction Direction
+}
+
+type EvtCharacterClosesDoor struct {
+	Character *Character
+	Direction Direction
+}
+
+type EvtCharacterLocksDoor struct {
+	Character *Character
+	Direction Direction
+}
+
+type EvtCharacterUnlocksDoor struct {
+	Character *Character
+	Direction Direction
+}
+
+// EvtDoorChanges is seen by characters on the other side of a door when it changes state.
+type EvtDoorChanges struct {
+	Direction Direction
+	State     DoorState
+}
+
+// EvtDoorIs tells the character what state a door is in when they cannot do what they asked with it.
+type EvtDoorIs struct {
+	Direction Direction
+	State     DoorState
+}
+
+// EvtDoorBlocksExit is sent when a character tries to walk through a door that is not open.
+type EvtDoorBlocksExit struct {
+	Direction Direction
+	State     DoorState
+}
+
+type EvtShopList struct {
+	Shop    *Shop
+	Entries []ShopEntry
+}
+
+// EvtItemValue tells the character what the shop would pay for one of the item.
+type EvtItemValue struct {
+	Shop  *Shop
+	Item  *Item
+	Price int64
+}
+
+type EvtCharacterBuysItem struct {
+	Character *Character
+	Shop      *Shop
+	Item      *Item
+	Price     int64
+}
+
+type EvtCharacterSellsItem struct {
+	Character *Character
+	Shop      *Shop
+	Item      *Item
+	Price     int64
+}
+
+type EvtShopDoesNotSell struct {
+	Shop  *Shop
+	Alias string
+}
+
+type EvtShopWillNotBuy struct {
+	Shop *Shop
+	Item *Item
+}
+
+type EvtCannotAfford struct {
+	Shop  *Shop
+	Price int64
+}
+
+type EvtNoShopHere struct {
+}
+
+type EvtCharacterCrafts struct {
+	Character *Character
+	Recipe    *Recipe
+	Items     []*Item
+}
+
+// EvtCraftingNeedsRoom is sent when a recipe has to be crafted in a room with a tag the character's room doesn't have.
+type EvtCraftingNeedsRoom struct {
+	Recipe *Recipe
+	Tag    string
+}
+
+type EvtCraftingNeedsTool struct {
+	Recipe *Recipe
+	Tool   *ItemDefinition
+}
+
+type EvtCraftingNeedsInput struct {
+	Recipe   *Recipe
+	Input    *ItemDefinition
+	Quantity int
+}
+
+type EvtQuestStarted struct {
+	Quest *Quest
+}
+
+// EvtQuestAdvanced is sent when a quest step is completed, Step is the next step.
+type EvtQuestAdvanced struct {
+	Quest *Quest
+	Step  *QuestStep
+}
+
+type EvtQuestCompleted struct {
+	Quest *Quest
+	Money int64
+	Items []*Item
+}
+
+type EvtQuestLog struct {
+	Character *Character
+	Quests    []*Quest
+}
+
+type EvtQuestDetails struct {
+	Quest    *Quest
+	Progress *QuestProgress
+}
+
+type EvtNobodyToTalkTo struct {
+	Target string
+}
+
+type EvtCharacterFollows struct {
+	Character *Character
+	Target    *Character
+}
+
+type EvtCharacterStopsFollowing struct {
+	Character *Character
+	Target    *Character
+}
+
+// EvtYouFollow is sent to a character just before they are moved along behind the character they follow.
+type EvtYouFollow struct {
+	Target *Character
+}
+
+type EvtNotFollowing struct {
+}
+
+type EvtCharacterNotFound struct {
+	Name string
+}
+
+// EvtGroupInvite is sent to both the character sending the invite and the target.
+type EvtGroupInvite struct {
+	Group     *Group
+	Character *Character
+	Target    *Character
+}
+
+type EvtGroupJoined struct {
+	Group     *Group
+	Character *Character
+}
+
+// EvtGroupLeft is sent to the group and to the character that left. NewLeader is set if the leader left.
+type EvtGroupLeft struct {
+	Group     *Group
+	Character *Character
+	Kicked    bool
+	NewLeader *Character
+}
+
+type EvtGroupList struct {
+	Group *Group
+}
+
+type EvtAlreadyInGroup struct {
+	Character *Character
+}
+
+type EvtNotInGroup struct {
+}
+
+type EvtNotGroupLeader struct {
+}
+
+type EvtNoGroupInvite struct {
+}
+
+type EvtPartyChat struct {
+	Character *Character
+	Content   string
+}
+
+type EvtWho struct {
+	Characters []*Character
+}
+
 type EvtNoExitInThatDirection struct {
 }
 
+type EvtNoDoorInThatDirection struct {
+}
+
+type EvtDoorHasNoLock struct {
+}
+
+type EvtYouDoNotHaveTheKey struct {
+}
+
 type EvtItemNotHere struct {
 }
 
@@ -79,3 +496,47 @@ type EvtNoSpaceToTakeItem struct {
 
 type EvtNoSpaceToStoreItem struct {
 }
+
+// EvtBuildFailed is sent when a builder's change could not be made or saved.
+type EvtBuildFailed struct {
+	Reason string
+}
+
+// EvtBuilderDigs is sent to the room when a builder makes a new room next to it.
+type EvtBuilderDigs struct {
+	Character *Character
+	Direction Direction
+	Room      *Room
+}
+
+type EvtRoomEdited struct {
+	Room  *Room
+	Field string
+}
+
+type EvtExitLinked struct {
+	Direction Direction
+	WorldID   WorldID
+	RoomID    RoomID
+}
+
+type EvtExitUnlinked struct {
+	Direction Direction
+}
+
+type EvtItemDefinitionCreated struct {
+	Definition *ItemDefinition
+}
+
+type EvtItemDefinitionEdited struct {
+	Definition *ItemDefinition
+	Field      string
+}
+
+type EvtDataReloaded struct {
+}
+
+// EvtDataReloadFailed lists the problems found in the data folder, one per line.
+type EvtDataReloadFailed struct {
+	Reason string
+}
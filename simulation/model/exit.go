@@ -1,8 +1,99 @@
 package model
 
+import "errors"
+
 // Exit is a structure that represents a destination room in the simulation.
 // By setting WorldID you can move the character into a new world, if none is set then the current world is assumed.
 type Exit struct {
 	RoomID  RoomID
 	WorldID WorldID
+
+	// Door is optional, exits without one can always be passed through
+	Door *Door
+
+	// Keyword is what players type to use a named exit, such as "enter portal". It is empty for compass exits.
+	Keyword string
+	// Name is how a named exit is described to players, such as "a shimmering portal".
+	Name string
+	// Hidden exits are not shown or usable until they are revealed by searching or by a script.
+	Hidden bool
+	// Condition is the name of a function in the room's script that can refuse to let characters through.
+	Condition string
+}
+
+// Label is the text used to show the exit to players, either the direction or the keyword.
+func (e *Exit) Label(direction Direction) string {
+	if e.Keyword != "" {
+		return e.Keyword
+	}
+	return direction.String()
+}
+
+// Leads returns true if the exit goes to the given room.
+func (e *Exit) Leads(worldID WorldID, roomID RoomID) bool {
+	return e.WorldID == worldID && e.RoomID == roomID
+}
+
+// Passable returns false if a closed door is in the way.
+func (e *Exit) Passable() bool {
+	return e.Door == nil || e.Door.State == DoorStateOpen
+}
+
+const (
+	DoorStateOpen DoorState = iota
+	DoorStateClosed
+	DoorStateLocked
+)
+
+// DoorState is an enum of the positions a door can be in.
+type DoorState byte
+
+func (s DoorState) String() string {
+	switch s {
+	case DoorStateOpen:
+		return "open"
+	case DoorStateClosed:
+		return "closed"
+	case DoorStateLocked:
+		return "locked"
+
+	default:
+		return "Invalid door state"
+	}
+}
+
+// StringToDoorState attempts to parse a string into a DoorState.
+// If unable, it returns DoorStateOpen and an error.
+func StringToDoorState(s string) (DoorState, error) {
+	switch s {
+	case "open":
+		return DoorStateOpen, nil
+	case "closed":
+		return DoorStateClosed, nil
+	case "locked":
+		return DoorStateLocked, nil
+
+	default:
+		return DoorStateOpen, errors.New("invalid door state")
+	}
+}
+
+// Door sits in an exit and can stop characters passing through it.
+// A door with a KeyID can be locked and unlocked by characters carrying that item.
+type Door struct {
+	State DoorState
+	KeyID ItemDefinitionID
+}
+
+// NewDoor is a helper function for creating a door. A keyID of 0 makes a door that cannot be locked.
+func NewDoor(state DoorState, keyID ItemDefinitionID) *Door {
+	return &Door{
+		State: state,
+		KeyID: keyID,
+	}
+}
+
+// Lockable returns true if the door has a key.
+func (d *Door) Lockable() bool {
+	return d.KeyID != 0
 }
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// GroupID is a type-aliased string, often set to a uuid.
+type GroupID string
+
+// Group is a party of characters adventuring together. Members follow the leader and share instances.
+type Group struct {
+	ID      GroupID
+	Leader  *Character
+	Members []*Character
+
+	invites map[CharacterID]bool
+}
+
+// NewGroup is a helper function for creating a group with the leader as its only member.
+func NewGroup(leader *Character) *Group {
+	return &Group{
+		ID:      GroupID(uuid.New().String()),
+		Leader:  leader,
+		Members: []*Character{leader},
+		invites: make(map[CharacterID]bool),
+	}
+}
+
+// Invite allows the character to join the group.
+func (g *Group) Invite(c *Character) {
+	g.invites[c.ID] = true
+}
+
+// Invited returns true if the character has been invited and hasn't joined yet.
+func (g *Group) Invited(c *Character) bool {
+	return g.invites[c.ID]
+}
+
+// Add puts the character in the group and uses up their invite.
+func (g *Group) Add(c *Character) {
+	delete(g.invites, c.ID)
+	g.Members = append(g.Members, c)
+}
+
+// Remove takes the character out of the group. If they were leading, the longest serving member takes over.
+func (g *Group) Remove(c *Character) {
+	for i, member := range g.Members {
+		if member == c {
+			g.Members = append(g.Members[:i], g.Members[i+1:]...)
+			break
+		}
+	}
+
+	if g.Leader == c && len(g.Members) > 0 {
+		g.Leader = g.Members[0]
+	}
+}
+
+// FindMember returns the member with the given name, or nil if there isn't one.
+func (g *Group) FindMember(name string) *Character {
+	for _, member := range g.Members {
+		if strings.EqualFold(member.Name, name) {
+			return member
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
@@ -9,12 +10,25 @@ import (
 type ItemDefinitionID int64
 
 type ItemDefinition struct {
-	ID        ItemDefinitionID
-	Name      string
-	Aliases   []string
-	Weight    int64 // grams
-	RigSlot   RigSlot
-	Container *ContainerDefinition
+	ID      ItemDefinitionID
+	Name    string
+	Aliases []string
+	Weight  int64 // grams
+	// RigSlot is the primary slot the item is equipped to
+	RigSlot RigSlot
+	// ExtraRigSlots are any other slots the item takes up while equipped, such as the off hand for two-handed weapons
+	ExtraRigSlots []RigSlot
+	Container     *ContainerDefinition
+	// ShortDescription is a brief line shown when the item is seen at a glance, such as on another character
+	ShortDescription string
+	// LongDescription is shown when the item is looked at
+	LongDescription string
+	// Stackable items of the same definition merge into a single item with a quantity, such as arrows or coins
+	Stackable bool
+	// PluralName is used when there is more than one item in a stack
+	PluralName string
+	// Value is the base price of the item, shops adjust it when buying and selling
+	Value int64
 }
 
 type ContainerDefinition struct {
@@ -26,19 +40,44 @@ type Item struct {
 	ID         ItemID
 	Definition *ItemDefinition
 	Container  Container
+	// Quantity is how many items are in this stack, it is always 1 for items that are not stackable
+	Quantity int
 }
 
-func NewItemDefinition(id ItemDefinitionID, name string, aliases []string, weight int64, RigSlot RigSlot, container *ContainerDefinition) *ItemDefinition {
+func NewItemDefinition(id ItemDefinitionID, name string, aliases []string, weight int64, rigSlot RigSlot, extraRigSlots []RigSlot, container *ContainerDefinition) *ItemDefinition {
 	return &ItemDefinition{
-		ID:        id,
-		Name:      name,
-		Aliases:   append(aliases, name),
-		Weight:    weight,
-		RigSlot:   RigSlot,
-		Container: container,
+		ID:            id,
+		Name:          name,
+		Aliases:       append(aliases, name),
+		Weight:        weight,
+		RigSlot:       rigSlot,
+		ExtraRigSlots: extraRigSlots,
+		Container:     container,
 	}
 }
 
+// Slots returns every rig slot the item occupies when equipped, starting with its primary slot.
+func (b *ItemDefinition) Slots() []RigSlot {
+	if b.RigSlot == RigSlotNone {
+		return nil
+	}
+	return append([]RigSlot{b.RigSlot}, b.ExtraRigSlots...)
+}
+
+// TwoHanded is true if the item needs both the main and off hand to be held.
+func (b *ItemDefinition) TwoHanded() bool {
+	var main, off bool
+	for _, slot := range b.Slots() {
+		switch slot {
+		case RigSlotMainHand:
+			main = true
+		case RigSlotOffHand:
+			off = true
+		}
+	}
+	return main && off
+}
+
 func (b *ItemDefinition) Spawn() *Item {
 	var container Container
 	if b.Container != nil {
@@ -48,15 +87,68 @@ func (b *ItemDefinition) Spawn() *Item {
 		ID:         ItemID(uuid.New().String()),
 		Definition: b,
 		Container:  container,
+		Quantity:   1,
 	}
 }
 
+// Plural returns the name used for more than one of this item.
+func (b *ItemDefinition) Plural() string {
+	if b.PluralName != "" {
+		return b.PluralName
+	}
+	return b.Name + "s"
+}
+
+// DisplayName is the item's name, with the quantity in front for stacks of more than one.
+func (b *Item) DisplayName() string {
+	if b.Quantity > 1 {
+		return fmt.Sprintf("%d %s", b.Quantity, b.Definition.Plural())
+	}
+	return b.Definition.Name
+}
+
+// Weight is the weight of the whole stack in grams.
+func (b *Item) Weight() int64 {
+	return b.Definition.Weight * int64(b.Quantity)
+}
+
+// Stacks returns true if the other item can be merged into this one.
+func (b *Item) Stacks(other *Item) bool {
+	return b != other &&
+		b.Definition == other.Definition &&
+		b.Definition.Stackable &&
+		b.Container == nil &&
+		other.Container == nil
+}
+
+// Split takes the given quantity off the stack as a new item, leaving the rest behind.
+// If the quantity is the whole stack or more, the item itself is returned.
+func (b *Item) Split(quantity int) *Item {
+	if quantity <= 0 || quantity >= b.Quantity || !b.Definition.Stackable {
+		return b
+	}
+
+	split := b.Definition.Spawn()
+	split.Quantity = quantity
+	b.Quantity -= quantity
+
+	return split
+}
+
 func (b *Item) KnownAs(alias string) bool {
-	if strings.ToLower(alias) == strings.ToLower(b.Definition.Name) {
+	return b.Definition.KnownAs(alias)
+}
+
+func (b *ItemDefinition) KnownAs(alias string) bool {
+	if strings.ToLower(alias) == strings.ToLower(b.Name) {
+		return true
+	}
+
+	if b.Stackable && strings.ToLower(alias) == strings.ToLower(b.Plural()) {
 		return true
 	}
 
-	for _, al := range b.Definition.Aliases {
+	for _, al := range b.Aliases {
 		if strings.ToLower(alias) == strings.ToLower(al) {
 			return true
 		}
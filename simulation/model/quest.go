@@ -0,0 +1,118 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+type QuestID int64
+
+const (
+	// QuestStepVisit is completed by entering a room
+	QuestStepVisit QuestStepType = iota
+	// QuestStepObtain is completed by carrying a quantity of an item
+	QuestStepObtain
+	// QuestStepTalk is completed by talking to someone
+	QuestStepTalk
+	// QuestStepFlag is completed when a script sets a flag on the character
+	QuestStepFlag
+)
+
+// QuestStepType is an enum of the kinds of goal a quest step can have.
+type QuestStepType byte
+
+func (t QuestStepType) String() string {
+	switch t {
+	case QuestStepVisit:
+		return "visit"
+	case QuestStepObtain:
+		return "obtain"
+	case QuestStepTalk:
+		return "talk"
+	case QuestStepFlag:
+		return "flag"
+
+	default:
+		return "Invalid quest step type"
+	}
+}
+
+// StringToQuestStepType attempts to parse a string into a QuestStepType.
+// If unable, it returns QuestStepVisit and an error.
+func StringToQuestStepType(s string) (QuestStepType, error) {
+	switch s {
+	case "visit":
+		return QuestStepVisit, nil
+	case "obtain":
+		return QuestStepObtain, nil
+	case "talk":
+		return QuestStepTalk, nil
+	case "flag":
+		return QuestStepFlag, nil
+
+	default:
+		return QuestStepVisit, errors.New("invalid quest step type")
+	}
+}
+
+// Quest is a series of goals that builders give to players, with a reward at the end.
+type Quest struct {
+	ID          QuestID
+	Name        string
+	Description string
+	Steps       []*QuestStep
+	RewardMoney int64
+	RewardItems []QuestRewardItem
+}
+
+// QuestStep is a single goal in a quest. Only the fields for the step's type are used.
+type QuestStep struct {
+	Type        QuestStepType
+	Description string
+
+	// visit
+	WorldID WorldID
+	RoomID  RoomID
+
+	// obtain, Item is looked up from ItemID when the quest is created
+	ItemID   ItemDefinitionID
+	Item     *ItemDefinition
+	Quantity int
+
+	// talk, the room is optional
+	NPC string
+
+	// flag
+	Flag string
+}
+
+type QuestRewardItem struct {
+	Definition *ItemDefinition
+	Quantity   int
+}
+
+// QuestProgress is how far a character has got through a quest.
+type QuestProgress struct {
+	Step      int
+	Completed bool
+}
+
+func (q *Quest) KnownAs(alias string) bool {
+	return strings.EqualFold(alias, q.Name)
+}
+
+// CurrentStep returns the step the character is working on, or nil if the quest is complete.
+func (q *Quest) CurrentStep(progress *QuestProgress) *QuestStep {
+	if progress.Completed || progress.Step >= len(q.Steps) {
+		return nil
+	}
+	return q.Steps[progress.Step]
+}
+
+// TalkedTo returns true if talking to the target in the room completes the step.
+func (s *QuestStep) TalkedTo(target string, room *Room) bool {
+	if s.Type != QuestStepTalk || !strings.EqualFold(s.NPC, target) {
+		return false
+	}
+	return s.RoomID == 0 || (room.WorldID == s.WorldID && room.ID == s.RoomID)
+}
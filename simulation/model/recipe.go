@@ -0,0 +1,49 @@
+package model
+
+import "strings"
+
+type RecipeID int64
+
+// Recipe describes how characters can craft items out of other items.
+type Recipe struct {
+	ID      RecipeID
+	Name    string
+	Aliases []string
+	// Inputs are consumed when the recipe is crafted
+	Inputs []RecipeComponent
+	// Tools have to be carried but are not consumed
+	Tools []*ItemDefinition
+	// RoomTag is a tag the room has to have for the recipe to be crafted there, such as "forge"
+	RoomTag string
+	// Outputs are spawned into the character's inventory
+	Outputs []RecipeComponent
+}
+
+// RecipeComponent is a quantity of an item used or made by a recipe.
+type RecipeComponent struct {
+	Definition *ItemDefinition
+	Quantity   int
+}
+
+func NewRecipe(id RecipeID, name string, aliases []string, roomTag string) *Recipe {
+	return &Recipe{
+		ID:      id,
+		Name:    name,
+		Aliases: aliases,
+		RoomTag: roomTag,
+	}
+}
+
+func (r *Recipe) KnownAs(alias string) bool {
+	if strings.EqualFold(alias, r.Name) {
+		return true
+	}
+
+	for _, al := range r.Aliases {
+		if strings.EqualFold(alias, al) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,5 +1,7 @@
 package model
 
+import "errors"
+
 // RigSlot is an enum of locations on a rig items can be equipped to.
 type RigSlot byte
 
@@ -8,38 +10,214 @@ const (
 	RigSlotNone RigSlot = iota
 	// RigSlotBackpack designates an item as wearable on the back.
 	RigSlotBackpack
+	// RigSlotHead designates an item as wearable on the head.
+	RigSlotHead
+	// RigSlotTorso designates an item as wearable on the torso.
+	RigSlotTorso
+	// RigSlotLegs designates an item as wearable on the legs.
+	RigSlotLegs
+	// RigSlotFeet designates an item as wearable on the feet.
+	RigSlotFeet
+	// RigSlotHands designates an item as wearable on the hands.
+	RigSlotHands
+	// RigSlotBelt designates an item as wearable around the waist.
+	RigSlotBelt
+	// RigSlotMainHand designates an item as held in the main hand.
+	RigSlotMainHand
+	// RigSlotOffHand designates an item as held in the off hand.
+	RigSlotOffHand
 )
 
+// RigSlots is every equipable rig slot, in the order they should be displayed.
+var RigSlots = []RigSlot{
+	RigSlotHead,
+	RigSlotTorso,
+	RigSlotHands,
+	RigSlotBelt,
+	RigSlotLegs,
+	RigSlotFeet,
+	RigSlotBackpack,
+	RigSlotMainHand,
+	RigSlotOffHand,
+}
+
+func (s RigSlot) String() string {
+	switch s {
+	case RigSlotNone:
+		return "none"
+	case RigSlotBackpack:
+		return "backpack"
+	case RigSlotHead:
+		return "head"
+	case RigSlotTorso:
+		return "torso"
+	case RigSlotLegs:
+		return "legs"
+	case RigSlotFeet:
+		return "feet"
+	case RigSlotHands:
+		return "hands"
+	case RigSlotBelt:
+		return "belt"
+	case RigSlotMainHand:
+		return "main_hand"
+	case RigSlotOffHand:
+		return "off_hand"
+
+	default:
+		return "Invalid rig slot"
+	}
+}
+
+// StringToRigSlot attempts to parse a string into a RigSlot.
+// An empty string is RigSlotNone. If unable, it returns RigSlotNone and an error.
+func StringToRigSlot(s string) (RigSlot, error) {
+	switch s {
+	case "", "none":
+		return RigSlotNone, nil
+	case "backpack":
+		return RigSlotBackpack, nil
+	case "head":
+		return RigSlotHead, nil
+	case "torso":
+		return RigSlotTorso, nil
+	case "legs":
+		return RigSlotLegs, nil
+	case "feet":
+		return RigSlotFeet, nil
+	case "hands":
+		return RigSlotHands, nil
+	case "belt":
+		return RigSlotBelt, nil
+	case "main_hand":
+		return RigSlotMainHand, nil
+	case "off_hand":
+		return RigSlotOffHand, nil
+
+	default:
+		return RigSlotNone, errors.New("invalid rig slot")
+	}
+}
+
 // Rig is a structure of various item mount points that represents where items can be equipped to.
+// An item that occupies more than one slot, such as a two-handed sword, is referenced from every slot it occupies.
 type Rig struct {
 	Backpack *Item
+	Head     *Item
+	Torso    *Item
+	Legs     *Item
+	Feet     *Item
+	Hands    *Item
+	Belt     *Item
+	MainHand *Item
+	OffHand  *Item
+}
+
+// Slot returns the item equipped in the given slot, or nil if it is empty.
+func (r *Rig) Slot(slot RigSlot) *Item {
+	if p := r.slot(slot); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Items returns every distinct item equipped on the rig, in display order.
+func (r *Rig) Items() []*Item {
+	var items []*Item
+	for _, slot := range RigSlots {
+		item := r.Slot(slot)
+		if item == nil || containsItem(items, item) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
 }
 
 func (r *Rig) FindItem(alias string) *Item {
-	if r.Backpack != nil && r.Backpack.KnownAs(alias) {
-		return r.Backpack
+	for _, item := range r.Items() {
+		if item.KnownAs(alias) {
+			return item
+		}
 	}
 
 	return nil
 }
 
-// Equip attempts to place the item on the rig in the item's designated rig slot.
-// When an item is equipped, you get a reference to the item that was already there returned back. This will be nil if nothing was equipped there.
+// Equip attempts to place the item on the rig in every rig slot the item occupies.
+// Any items that were already in those slots are removed from the rig entirely and returned, so a shield
+// equipped over a two-handed sword will also free up the sword's other hand. The slice is empty if nothing was displaced.
 // If the item is not equippable for any reason you get an error.
-func (r *Rig) Equip(item *Item) (*Item, error) {
+func (r *Rig) Equip(item *Item) ([]*Item, error) {
 	if item.Definition.RigSlot == RigSlotNone {
 		return nil, ErrNotEquipable
 	}
-	oldBackpack := r.Backpack
-	r.Backpack = item
-	return oldBackpack, nil
+
+	var displaced []*Item
+	for _, slot := range item.Definition.Slots() {
+		old := r.Slot(slot)
+		if old == nil || old == item || containsItem(displaced, old) {
+			continue
+		}
+		displaced = append(displaced, old)
+	}
+
+	for _, old := range displaced {
+		r.Unequip(old)
+	}
+
+	for _, slot := range item.Definition.Slots() {
+		if p := r.slot(slot); p != nil {
+			*p = item
+		}
+	}
+
+	return displaced, nil
+}
+
+// Unequip clears the item out of every rig slot it occupies.
+// It returns false if the item was not on the rig.
+func (r *Rig) Unequip(item *Item) bool {
+	found := false
+	for _, slot := range RigSlots {
+		if p := r.slot(slot); p != nil && *p == item {
+			*p = nil
+			found = true
+		}
+	}
+
+	return found
 }
 
-func (r *Rig) Unequip(Item *Item) bool {
-	if r.Backpack == Item {
-		r.Backpack = nil
-		return true
+func (r *Rig) slot(slot RigSlot) **Item {
+	switch slot {
+	case RigSlotBackpack:
+		return &r.Backpack
+	case RigSlotHead:
+		return &r.Head
+	case RigSlotTorso:
+		return &r.Torso
+	case RigSlotLegs:
+		return &r.Legs
+	case RigSlotFeet:
+		return &r.Feet
+	case RigSlotHands:
+		return &r.Hands
+	case RigSlotBelt:
+		return &r.Belt
+	case RigSlotMainHand:
+		return &r.MainHand
+	case RigSlotOffHand:
+		return &r.OffHand
 	}
+	return nil
+}
 
+func containsItem(items []*Item, item *Item) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
 	return false
 }
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -17,10 +19,17 @@ type Room struct {
 	Container   Container
 	Characters  []*Character
 	Exits       map[Direction]*Exit
+	NamedExits  map[string]*Exit
+	Extras      []*ExtraDescription
+	// ShopID is the shop that trades in this room, 0 if there isn't one
+	ShopID ShopID
+	// Tags mark rooms as having something special in them, such as a forge
+	Tags []string
 
 	Alone bool
 
-	Lua *lua.LState
+	Lua  *lua.LState
+	Host ScriptHost
 }
 
 func NewRoom(roomID RoomID, worldID WorldID, name, region, description, script string, alone bool) (r *Room) {
@@ -40,8 +49,11 @@ func NewRoom(roomID RoomID, worldID WorldID, name, region, description, script s
 			DirectionSouthWest: nil,
 			DirectionWest:      nil,
 			DirectionNorthWest: nil,
+			DirectionUp:        nil,
+			DirectionDown:      nil,
 		},
-		Container: NewRoomContainer(),
+		NamedExits: make(map[string]*Exit),
+		Container:  NewRoomContainer(),
 
 		Alone: alone,
 
@@ -65,6 +77,11 @@ func (r *Room) UpdateScript(script string) {
 	r.Lua = r.createScriptRuntime(ScriptContext{r})
 }
 
+// Script returns the source of the room's script.
+func (r *Room) Script() string {
+	return r.script
+}
+
 func (r *Room) AddCharacter(c *Character) {
 	r.Characters = append(r.Characters, c)
 }
@@ -87,6 +104,86 @@ func (r *Room) FindItem(alias string) *Item {
 	return nil
 }
 
+// FindExtra returns the extra description that has the given keyword, or nil if there isn't one.
+func (r *Room) FindExtra(keyword string) *ExtraDescription {
+	for _, extra := range r.Extras {
+		if extra.KnownAs(keyword) {
+			return extra
+		}
+	}
+	return nil
+}
+
+// HasTag returns true if the room is marked with the tag.
+func (r *Room) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindCharacter returns the character in the room with the given name, or nil if there isn't one.
+func (r *Room) FindCharacter(name string) *Character {
+	for _, ch := range r.Characters {
+		if strings.EqualFold(ch.Name, name) {
+			return ch
+		}
+	}
+	return nil
+}
+
+// FindExit looks up an exit by direction name or by named exit keyword.
+// Short forms of directions are not understood here.
+func (r *Room) FindExit(key string) *Exit {
+	key = strings.ToLower(key)
+	if direction, err := StringToDirection(key); err == nil {
+		return r.Exits[direction]
+	}
+	return r.NamedExits[key]
+}
+
+// FindNamedExit matches what a player typed against the keywords of the named exits in the room.
+// The player can type the whole keyword, such as "climb ladder", or just the last word of it, such as "ladder".
+// Hidden exits are never matched.
+func (r *Room) FindNamedExit(text string) *Exit {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if exit, ok := r.NamedExits[text]; ok && !exit.Hidden {
+		return exit
+	}
+
+	for keyword, exit := range r.NamedExits {
+		if exit.Hidden {
+			continue
+		}
+		words := strings.Fields(keyword)
+		if len(words) > 0 && words[len(words)-1] == text {
+			return exit
+		}
+	}
+
+	return nil
+}
+
+// ExitLeadingTo looks for an exit in this room that goes to the given room.
+// Compass exits are preferred over named exits. The keyword is empty if a compass exit is returned.
+func (r *Room) ExitLeadingTo(worldID WorldID, roomID RoomID) (Direction, string, *Exit) {
+	for _, direction := range Directions {
+		if exit := r.Exits[direction]; exit != nil && exit.Leads(worldID, roomID) {
+			return direction, "", exit
+		}
+	}
+
+	for keyword, exit := range r.NamedExits {
+		if exit.Leads(worldID, roomID) {
+			return DirectionNorth, keyword, exit
+		}
+	}
+
+	return DirectionNorth, "", nil
+}
+
 func (r *Room) Dispatch(event interface{}) {
 	for _, ch := range r.Characters {
 		ch.Dispatch(event)
@@ -111,6 +208,31 @@ func (r *Room) OnExit(c *Character) {
 	}
 }
 
+func (r *Room) OnSearch(c *Character) {
+	if r.Lua != nil {
+		callFunction(r.Lua, "onSearch", lua.LString(c.ID))
+	}
+}
+
+// OnTalk runs the room's talk hook, it returns false if the room's script doesn't have one.
+func (r *Room) OnTalk(c *Character, target string) bool {
+	if r.Lua == nil || r.Lua.GetGlobal("onTalk").Type() == lua.LTNil {
+		return false
+	}
+	callFunction(r.Lua, "onTalk", lua.LString(c.ID), lua.LString(target))
+	return true
+}
+
+// CanTraverse runs the exit's condition function, if it has one.
+// The function is called with the character ID and the exit label and can return false and a message to refuse passage.
+func (r *Room) CanTraverse(c *Character, label string, exit *Exit) (bool, string) {
+	if exit.Condition == "" || r.Lua == nil {
+		return true, ""
+	}
+
+	return callCondition(r.Lua, exit.Condition, lua.LString(c.ID), lua.LString(label))
+}
+
 func (r *Room) getAwakeCharacters() []*Character {
 	var result []*Character
 	for _, ch := range r.Characters {
@@ -120,3 +242,18 @@ func (r *Room) getAwakeCharacters() []*Character {
 	}
 	return result
 }
+
+// ExtraDescription is a piece of scenery in a room that players can look at, such as a fountain or an inscription.
+type ExtraDescription struct {
+	Keywords    []string
+	Description string
+}
+
+func (e *ExtraDescription) KnownAs(keyword string) bool {
+	for _, k := range e.Keywords {
+		if strings.EqualFold(k, keyword) {
+			return true
+		}
+	}
+	return false
+}
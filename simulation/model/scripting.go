@@ -17,6 +17,15 @@ func (s *scriptedObject) createScriptRuntime(context ScriptContext) *lua.LState
 
 	L.SetGlobal("sleep", L.NewFunction(context.Sleep))
 	L.SetGlobal("narrate", L.NewFunction(context.Narrate))
+	L.SetGlobal("getDoor", L.NewFunction(context.GetDoor))
+	L.SetGlobal("setDoor", L.NewFunction(context.SetDoor))
+	L.SetGlobal("revealExit", L.NewFunction(context.RevealExit))
+	L.SetGlobal("hideExit", L.NewFunction(context.HideExit))
+	L.SetGlobal("startQuest", L.NewFunction(context.StartQuest))
+	L.SetGlobal("advanceQuest", L.NewFunction(context.AdvanceQuest))
+	L.SetGlobal("completeQuest", L.NewFunction(context.CompleteQuest))
+	L.SetGlobal("setFlag", L.NewFunction(context.SetFlag))
+	L.SetGlobal("hasFlag", L.NewFunction(context.HasFlag))
 
 	if err := L.DoString(s.script); err != nil {
 		panic(err)
@@ -39,6 +48,42 @@ func callFunction(L *lua.LState, name string, params ...lua.LValue) {
 	}
 }
 
+// ScriptHost is implemented by the simulation so that scripts can make changes that reach beyond their own room.
+type ScriptHost interface {
+	SetDoorState(room *Room, direction Direction, state DoorState) error
+	StartQuest(characterID CharacterID, questID QuestID) error
+	AdvanceQuest(characterID CharacterID, questID QuestID) error
+	CompleteQuest(characterID CharacterID, questID QuestID) error
+	SetFlag(characterID CharacterID, flag string, value bool) error
+	HasFlag(characterID CharacterID, flag string) bool
+}
+
+// callCondition calls a function that decides whether something is allowed.
+// If the function does not exist or returns nothing, it is allowed.
+// A refusal can come with a message to show the player.
+func callCondition(L *lua.LState, name string, params ...lua.LValue) (bool, string) {
+	if L.GetGlobal(name).Type() == lua.LTNil {
+		return true, ""
+	}
+
+	if err := L.CallByParam(lua.P{
+		Fn:      L.GetGlobal(name),
+		NRet:    2,
+		Protect: true,
+	}, params...); err != nil {
+		logging.Error(err.Error())
+		return true, ""
+	}
+
+	allowed, message := L.Get(-2), L.Get(-1)
+	L.Pop(2)
+
+	if allowed.Type() == lua.LTNil {
+		return true, ""
+	}
+	return lua.LVAsBool(allowed), lua.LVAsString(message)
+}
+
 type ScriptContext struct {
 	Room *Room
 }
@@ -67,3 +112,137 @@ func (ctx *ScriptContext) Narrate(L *lua.LState) int {
 
 	return 0
 }
+
+// GetDoor returns the state of the door in the given direction, or nil if there is no door there.
+func (ctx *ScriptContext) GetDoor(L *lua.LState) int {
+	direction, err := StringToDirection(L.ToString(1))
+	if err != nil {
+		L.ArgError(1, err.Error())
+		return 0
+	}
+
+	exit := ctx.Room.Exits[direction]
+	if exit == nil || exit.Door == nil {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	L.Push(lua.LString(exit.Door.State.String()))
+	return 1
+}
+
+// SetDoor changes the state of the door in the given direction, as well as the door on the other side.
+func (ctx *ScriptContext) SetDoor(L *lua.LState) int {
+	direction, err := StringToDirection(L.ToString(1))
+	if err != nil {
+		L.ArgError(1, err.Error())
+		return 0
+	}
+
+	state, err := StringToDoorState(L.ToString(2))
+	if err != nil {
+		L.ArgError(2, err.Error())
+		return 0
+	}
+
+	if ctx.Room.Host == nil {
+		return 0
+	}
+
+	if err := ctx.Room.Host.SetDoorState(ctx.Room, direction, state); err != nil {
+		L.RaiseError(err.Error())
+	}
+
+	return 0
+}
+
+// RevealExit makes a hidden exit visible. The exit is given by direction or keyword.
+func (ctx *ScriptContext) RevealExit(L *lua.LState) int {
+	exit := ctx.Room.FindExit(L.ToString(1))
+	if exit == nil {
+		L.ArgError(1, "no such exit")
+		return 0
+	}
+
+	if exit.Hidden {
+		exit.Hidden = false
+		ctx.Room.Dispatch(EvtExitRevealed{Label: L.ToString(1)})
+	}
+
+	return 0
+}
+
+// HideExit hides an exit again. The exit is given by direction or keyword.
+func (ctx *ScriptContext) HideExit(L *lua.LState) int {
+	exit := ctx.Room.FindExit(L.ToString(1))
+	if exit == nil {
+		L.ArgError(1, "no such exit")
+		return 0
+	}
+
+	exit.Hidden = true
+
+	return 0
+}
+
+// StartQuest gives the character a quest.
+func (ctx *ScriptContext) StartQuest(L *lua.LState) int {
+	return ctx.questFunction(L, ScriptHost.StartQuest)
+}
+
+// AdvanceQuest moves the character on to the next step of a quest.
+func (ctx *ScriptContext) AdvanceQuest(L *lua.LState) int {
+	return ctx.questFunction(L, ScriptHost.AdvanceQuest)
+}
+
+// CompleteQuest finishes a quest for the character and hands out the rewards.
+func (ctx *ScriptContext) CompleteQuest(L *lua.LState) int {
+	return ctx.questFunction(L, ScriptHost.CompleteQuest)
+}
+
+// questFunction reads the character ID and quest ID arguments and passes them to the host.
+func (ctx *ScriptContext) questFunction(L *lua.LState, fn func(ScriptHost, CharacterID, QuestID) error) int {
+	characterID := CharacterID(L.ToString(1))
+	questID := QuestID(L.ToInt64(2))
+
+	if ctx.Room.Host == nil {
+		return 0
+	}
+
+	if err := fn(ctx.Room.Host, characterID, questID); err != nil {
+		L.RaiseError(err.Error())
+	}
+
+	return 0
+}
+
+// SetFlag sets a named flag on the character, which quests can wait for. Pass false as the third argument to clear it.
+func (ctx *ScriptContext) SetFlag(L *lua.LState) int {
+	characterID := CharacterID(L.ToString(1))
+	flag := L.ToString(2)
+	value := L.OptBool(3, true)
+
+	if ctx.Room.Host == nil {
+		return 0
+	}
+
+	if err := ctx.Room.Host.SetFlag(characterID, flag, value); err != nil {
+		L.RaiseError(err.Error())
+	}
+
+	return 0
+}
+
+// HasFlag returns true if the flag is set on the character.
+func (ctx *ScriptContext) HasFlag(L *lua.LState) int {
+	characterID := CharacterID(L.ToString(1))
+	flag := L.ToString(2)
+
+	if ctx.Room.Host == nil {
+		L.Push(lua.LFalse)
+		return 1
+	}
+
+	L.Push(lua.LBool(ctx.Room.Host.HasFlag(characterID, flag)))
+	return 1
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type ShopID int64
+
+// Shop is a vendor that sells items to characters and buys them back.
+// Shops are attached to rooms and are run by a keeper, who is named in the shop's messages.
+type Shop struct {
+	ID     ShopID
+	Name   string
+	Keeper string
+	// BuyMultiplier is applied to an item's value to get the price characters pay the shop
+	BuyMultiplier float64
+	// SellMultiplier is applied to an item's value to get the price the shop pays characters
+	SellMultiplier float64
+	// Stock is the number of each item the shop is restocked up to
+	Stock map[ItemDefinitionID]int
+	// RestockInterval is how often the shop restocks, zero means never
+	RestockInterval time.Duration
+
+	definitions map[ItemDefinitionID]*ItemDefinition
+	inventory   map[ItemDefinitionID]int
+	lastRestock time.Time
+}
+
+// ShopEntry is a line in a shop's list of goods.
+type ShopEntry struct {
+	Definition *ItemDefinition
+	Count      int
+	Price      int64
+}
+
+func NewShop(id ShopID, name, keeper string, buyMultiplier, sellMultiplier float64, restockInterval time.Duration) *Shop {
+	return &Shop{
+		ID:              id,
+		Name:            name,
+		Keeper:          keeper,
+		BuyMultiplier:   buyMultiplier,
+		SellMultiplier:  sellMultiplier,
+		Stock:           make(map[ItemDefinitionID]int),
+		RestockInterval: restockInterval,
+		definitions:     make(map[ItemDefinitionID]*ItemDefinition),
+		inventory:       make(map[ItemDefinitionID]int),
+	}
+}
+
+// AddStock sets how many of the item the shop restocks up to, and fills the shop up to that level.
+func (s *Shop) AddStock(definition *ItemDefinition, quantity int) {
+	s.definitions[definition.ID] = definition
+	s.Stock[definition.ID] = quantity
+	if s.inventory[definition.ID] < quantity {
+		s.inventory[definition.ID] = quantity
+	}
+}
+
+// Restock tops up every stocked item if the restock interval has passed.
+// It returns true if the shop restocked.
+func (s *Shop) Restock(now time.Time) bool {
+	if s.RestockInterval == 0 || now.Sub(s.lastRestock) < s.RestockInterval {
+		return false
+	}
+
+	s.lastRestock = now
+	for id, quantity := range s.Stock {
+		if s.inventory[id] < quantity {
+			s.inventory[id] = quantity
+		}
+	}
+
+	return true
+}
+
+// List returns everything the shop has for sale, sorted by name.
+func (s *Shop) List() []ShopEntry {
+	var entries []ShopEntry
+	for id, count := range s.inventory {
+		if count <= 0 {
+			continue
+		}
+		definition := s.definitions[id]
+		entries = append(entries, ShopEntry{
+			Definition: definition,
+			Count:      count,
+			Price:      s.BuyPrice(definition),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Definition.Name < entries[j].Definition.Name
+	})
+
+	return entries
+}
+
+// FindForSale returns the definition of an item the shop has in stock that matches the alias.
+func (s *Shop) FindForSale(alias string) (*ItemDefinition, int) {
+	for id, count := range s.inventory {
+		if count <= 0 {
+			continue
+		}
+		if definition := s.definitions[id]; definition.KnownAs(alias) {
+			return definition, count
+		}
+	}
+	return nil, 0
+}
+
+// Trades returns true if the shop stocks the item, or has some of it that were sold to it.
+func (s *Shop) Trades(id ItemDefinitionID) bool {
+	_, stocked := s.Stock[id]
+	return stocked || s.inventory[id] > 0
+}
+
+// Take removes items that have been bought from the shop's inventory.
+func (s *Shop) Take(definition *ItemDefinition, quantity int) {
+	s.inventory[definition.ID] -= quantity
+}
+
+// Give adds items that have been sold to the shop to its inventory, so that they can be bought back.
+func (s *Shop) Give(definition *ItemDefinition, quantity int) {
+	s.definitions[definition.ID] = definition
+	s.inventory[definition.ID] += quantity
+}
+
+// BuyPrice is how much a character pays the shop for one of the item.
+func (s *Shop) BuyPrice(definition *ItemDefinition) int64 {
+	return int64(math.Ceil(float64(definition.Value) * s.BuyMultiplier))
+}
+
+// SellPrice is how much the shop pays a character for one of the item.
+func (s *Shop) SellPrice(definition *ItemDefinition) int64 {
+	return int64(math.Floor(float64(definition.Value) * s.SellMultiplier))
+}
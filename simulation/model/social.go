@@ -0,0 +1,49 @@
+package model
+
+// Social is an emote with canned messages, such as smile or wave, that can be aimed at another character.
+type Social struct {
+	Name string
+
+	// Untargeted is used when the social is used on its own
+	Untargeted SocialMessages
+	// Targeted is used when the social is aimed at another character
+	Targeted SocialMessages
+	// Self is used when the character aims the social at themself
+	Self SocialMessages
+}
+
+// SocialMessages are the templates shown to each character that sees a social.
+// In the templates $n is replaced with the name of the character performing the social and $N with the target's name.
+type SocialMessages struct {
+	Actor  string
+	Target string
+	Others string
+}
+
+func NewSocial(name string, untargeted, targeted, self SocialMessages) *Social {
+	return &Social{
+		Name:       name,
+		Untargeted: untargeted,
+		Targeted:   targeted,
+		Self:       self,
+	}
+}
+
+// Messages picks the templates for the target, falling back to the untargeted messages if the social has none.
+func (s *Social) Messages(actor, target *Character) SocialMessages {
+	switch {
+	case target == nil:
+		return s.Untargeted
+	case target == actor && s.Self.Actor != "":
+		return s.Self
+	case target != actor && s.Targeted.Actor != "":
+		return s.Targeted
+	default:
+		return s.Untargeted
+	}
+}
+
+// Targetable returns true if the social can be aimed at another character.
+func (s *Social) Targetable() bool {
+	return s.Targeted.Actor != ""
+}
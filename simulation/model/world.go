@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type WorldID string
 
 type World struct {
@@ -13,6 +15,22 @@ type World struct {
 	Instancable bool
 	// Instance marks this world has an instance
 	Instance bool
+	// Template is the instancable world an instance was copied from
+	Template WorldID
+
+	// SpawnRoom is where characters arrive in the world, zero if it has none
+	SpawnRoom RoomID
+	// RecallRoom is where characters are sent back to when their room in the world is gone, zero to use the spawn room
+	RecallRoom RoomID
+	// RecallWorldID is the world the recall room is in, if it isn't this one
+	RecallWorldID WorldID
+	// InstanceTimeout is how long instances of the world are kept once everyone has left, zero keeps them forever
+	InstanceTimeout time.Duration
+	// EmptySince is when the last awake character left an instance
+	EmptySince time.Time
+	// MinLevel and MaxLevel are the levels the world is meant for, zero if there is no limit
+	MinLevel int
+	MaxLevel int
 }
 
 func NewWorld(id WorldID, instancable bool, instance, alone bool) *World {
@@ -23,5 +41,6 @@ func NewWorld(id WorldID, instancable bool, instance, alone bool) *World {
 
 		Alone:       alone,
 		Instancable: instancable,
+		Instance:    instance,
 	}
 }
@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/soupstoregames/coda-mud/simulation/data/state"
@@ -21,12 +22,32 @@ func (s *Simulation) Save(p state.Persister) error {
 
 	start := time.Now()
 
+	s.characterLock.Lock()
 	for i := range s.characters {
 		p.QueueCharacter(characterToState(s.characters[i]))
 	}
+	deleted := s.deletedCharacters
+	s.deletedCharacters = nil
 
+	// instances come and go while the simulation runs, so the worlds are copied while it is held still
+	var worlds []state.World
 	for i := range s.worlds {
-		p.QueueWorld(worldToState(s.worlds[i]))
+		// instances are thrown away when the server stops
+		if s.worlds[i].Instance {
+			continue
+		}
+		worlds = append(worlds, worldToState(s.worlds[i]))
+	}
+	s.characterLock.Unlock()
+
+	for _, id := range deleted {
+		if err := p.DeleteCharacter(string(id)); err != nil {
+			logging.Warn(fmt.Sprintf("Failed to delete character %s: %s", id, err.Error()))
+		}
+	}
+
+	for _, world := range worlds {
+		p.QueueWorld(world)
 	}
 
 	err := p.Persist()
@@ -44,31 +65,57 @@ func (s *Simulation) Load(characters []state.Character, worlds []state.World) er
 	for _, ch := range characters {
 		room, err := s.GetRoom(model.WorldID(ch.World), model.RoomID(ch.Room))
 		if err != nil {
-			return err
+			// characters saved inside an instance, or a room that has since been removed, go back to the world's recall room
+			if room = s.recallRoom(model.WorldID(ch.World)); room == nil {
+				return err
+			}
+			logging.Warn(fmt.Sprintf("Character %s was in missing room %d in world %s, moving to room %d in world %s", ch.Name, ch.Room, ch.World, room.ID, room.WorldID))
 		}
 
 		// create new character
 		character := model.NewCharacter(ch.Name, room)
 		character.ID = model.CharacterID(ch.ID)
+		character.Money = ch.Money
+
+		for _, q := range ch.Quests {
+			character.Quests[model.QuestID(q.ID)] = &model.QuestProgress{Step: q.Step, Completed: q.Completed}
+		}
+		for _, flag := range ch.Flags {
+			character.Flags[flag] = true
+		}
+		character.Channels = ch.Channels
+		character.Description = ch.Description
+		character.Attributes = model.Attributes(ch.Attributes)
+		character.Background = model.BackgroundID(ch.Background)
 
 		// equip character's rig
-		if ch.Rig.Backpack != nil {
-			itemDefinition := s.itemDefinitions[model.ItemDefinitionID(ch.Rig.Backpack.ItemDefinition)]
-			item := itemDefinition.Spawn()
-			item.ID = model.ItemID(ch.Rig.Backpack.ID)
-			character.Rig.Backpack = item
+		for _, i := range rigToList(ch.Rig) {
+			item, ok := s.itemFromState(i)
+			if !ok {
+				logging.Error("failed to load rig item for character")
+				continue
+			}
+
+			displaced, err := character.Rig.Equip(item)
+			if err != nil {
+				// the definition is no longer equipable, so keep hold of the item
+				character.Container.PutItem(item)
+				continue
+			}
+			// definitions that have moved to the same slot push each other off, so keep hold of those too
+			for _, old := range displaced {
+				character.Container.PutItem(old)
+			}
 		}
 
 		// @spawn in character's items
 		for _, i := range ch.Items {
-			definition, ok := s.itemDefinitions[model.ItemDefinitionID(i.ItemDefinition)]
+			item, ok := s.itemFromState(i)
 			if !ok {
 				logging.Error("failed to load item for character")
 				continue
 			}
 
-			item := definition.Spawn()
-			item.ID = model.ItemID(i.ID)
 			character.Container.PutItem(item)
 		}
 
@@ -96,16 +143,36 @@ func (s *Simulation) Load(characters []state.Character, worlds []state.World) er
 			}
 
 			for _, i := range r.Items {
-				definition, ok := s.itemDefinitions[model.ItemDefinitionID(i.ItemDefinition)]
+				item, ok := s.itemFromState(i)
 				if !ok {
 					logging.Warn(fmt.Sprintf("Tried to load item for non-existant definition %d in room %d in world %s", i.ItemDefinition, r.ID, w.ID))
 					continue
 				}
 
-				item := definition.Spawn()
-				item.ID = model.ItemID(i.ID)
 				room.Container.PutItem(item)
 			}
+
+			for d, st := range r.Doors {
+				direction, err := model.StringToDirection(d)
+				if err != nil {
+					logging.Warn(fmt.Sprintf("Tried to load door for invalid direction %s in room %d in world %s", d, r.ID, w.ID))
+					continue
+				}
+
+				doorState, err := model.StringToDoorState(st)
+				if err != nil {
+					logging.Warn(fmt.Sprintf("Tried to load invalid door state %s in room %d in world %s", st, r.ID, w.ID))
+					continue
+				}
+
+				exit := room.Exits[direction]
+				if exit == nil || exit.Door == nil {
+					logging.Warn(fmt.Sprintf("Tried to load state for non-existant door %s in room %d in world %s", d, r.ID, w.ID))
+					continue
+				}
+
+				exit.Door.State = doorState
+			}
 		}
 	}
 
@@ -114,19 +181,111 @@ func (s *Simulation) Load(characters []state.Character, worlds []state.World) er
 
 func characterToState(c *model.Character) state.Character {
 	return state.Character{
-		ID:    string(c.ID),
-		Name:  c.Name,
-		Room:  int64(c.Room.ID),
-		World: string(c.Room.WorldID),
-		Rig:   mapRig(c.Rig),
-		Items: mapContents(c.Container),
+		ID:       string(c.ID),
+		Name:     c.Name,
+		Room:     int64(c.Room.ID),
+		World:    string(c.Room.WorldID),
+		Rig:      mapRig(c.Rig),
+		Items:    mapContents(c.Container),
+		Money:    c.Money,
+		Quests:   mapQuests(c.Quests),
+		Flags:    mapFlags(c.Flags),
+		Channels: c.Channels,
+
+		Description: c.Description,
+		Attributes:  state.Attributes(c.Attributes),
+		Background:  int64(c.Background),
 	}
 }
 
+func mapQuests(quests map[model.QuestID]*model.QuestProgress) []state.QuestProgress {
+	var result []state.QuestProgress
+	for id, progress := range quests {
+		result = append(result, state.QuestProgress{
+			ID:        int64(id),
+			Step:      progress.Step,
+			Completed: progress.Completed,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func mapFlags(flags map[string]bool) []string {
+	var result []string
+	for flag, set := range flags {
+		if set {
+			result = append(result, flag)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// itemFromState spawns an item and anything stored inside it from its saved state.
+// It returns false if the item's definition no longer exists.
+func (s *Simulation) itemFromState(i *state.Item) (*model.Item, bool) {
+	definition, ok := s.itemDefinitions[model.ItemDefinitionID(i.ItemDefinition)]
+	if !ok {
+		return nil, false
+	}
+
+	item := definition.Spawn()
+	item.ID = model.ItemID(i.ID)
+	if i.Quantity > 0 {
+		item.Quantity = i.Quantity
+	}
+
+	if item.Container != nil {
+		for _, child := range i.Items {
+			childItem, ok := s.itemFromState(child)
+			if !ok {
+				logging.Warn(fmt.Sprintf("Tried to load item for non-existant definition %d in item %s", child.ItemDefinition, i.ID))
+				continue
+			}
+			item.Container.PutItem(childItem)
+		}
+	}
+
+	return item, true
+}
+
+// mapRig saves every equipped item once, under the first slot it occupies, so that items occupying several slots are not duplicated.
+// It doesn't go by the item's definition, which may have changed slots since the item was equipped.
+// Loading equips each item into the slots its definition has by then.
 func mapRig(r *model.Rig) state.Rig {
+	saved := make(map[model.RigSlot]*state.Item)
+	seen := make(map[*model.Item]bool)
+	for _, slot := range model.RigSlots {
+		item := r.Slot(slot)
+		if item == nil || seen[item] {
+			continue
+		}
+		seen[item] = true
+		saved[slot] = mapItem(item)
+	}
+
 	return state.Rig{
-		Backpack: mapItem(r.Backpack),
+		Backpack: saved[model.RigSlotBackpack],
+		Head:     saved[model.RigSlotHead],
+		Torso:    saved[model.RigSlotTorso],
+		Legs:     saved[model.RigSlotLegs],
+		Feet:     saved[model.RigSlotFeet],
+		Hands:    saved[model.RigSlotHands],
+		Belt:     saved[model.RigSlotBelt],
+		MainHand: saved[model.RigSlotMainHand],
+		OffHand:  saved[model.RigSlotOffHand],
+	}
+}
+
+func rigToList(r state.Rig) []*state.Item {
+	var items []*state.Item
+	for _, i := range []*state.Item{r.Backpack, r.Head, r.Torso, r.Legs, r.Feet, r.Hands, r.Belt, r.MainHand, r.OffHand} {
+		if i != nil {
+			items = append(items, i)
+		}
 	}
+	return items
 }
 
 func worldToState(w *model.World) state.World {
@@ -142,11 +301,23 @@ func mapRoomstoState(r map[model.RoomID]*model.Room) []state.Room {
 		rooms = append(rooms, state.Room{
 			ID:    int64(v.ID),
 			Items: mapContents(v.Container),
+			Doors: mapDoors(v.Exits),
 		})
 	}
 	return rooms
 }
 
+func mapDoors(exits map[model.Direction]*model.Exit) map[string]string {
+	doors := make(map[string]string)
+	for direction, exit := range exits {
+		if exit == nil || exit.Door == nil {
+			continue
+		}
+		doors[direction.String()] = exit.Door.State.String()
+	}
+	return doors
+}
+
 func mapItem(i *model.Item) *state.Item {
 	if i == nil {
 		return nil
@@ -155,6 +326,7 @@ func mapItem(i *model.Item) *state.Item {
 	return &state.Item{
 		ID:             string(i.ID),
 		ItemDefinition: int64(i.Definition.ID),
+		Quantity:       i.Quantity,
 		Items:          mapContents(i.Container),
 	}
 }
@@ -13,21 +13,51 @@ func (s *Simulation) say(actor *model.Character, c model.CommandSay) {
 }
 
 func (s *Simulation) move(actor *model.Character, c model.CommandMove) {
+	// everyone following the actor goes into the same instance as them, whether or not they are grouped
+	s.moveFollowed(actor, nil, c, instanceKey(actor))
+}
+
+// moveFollowed moves the actor through the exit and brings everyone following them along.
+// The leader is who the actor is following, if they are following someone, and key is the instance the whole party goes into.
+func (s *Simulation) moveFollowed(actor, leader *model.Character, c model.CommandMove, key string) {
 	// save the actor's current room
 	originalRoom := actor.Room
 
-	// look for an exit in the direction the user specified
-	exit := actor.Room.Exits[c.Direction]
-	if exit == nil {
+	// look for the exit the user specified, either named or in a direction
+	var exit *model.Exit
+	if c.Keyword != "" {
+		exit = actor.Room.NamedExits[c.Keyword]
+	} else {
+		exit = actor.Room.Exits[c.Direction]
+	}
+	if exit == nil || exit.Hidden {
 		actor.Dispatch(model.EvtNoExitInThatDirection{})
 		return
 	}
 
+	// closed doors have to be opened first
+	if !exit.Passable() {
+		actor.Dispatch(model.EvtDoorBlocksExit{Direction: c.Direction, State: exit.Door.State})
+		return
+	}
+
+	// the room's script can stop the character going through
+	if ok, message := originalRoom.CanTraverse(actor, exit.Label(c.Direction), exit); !ok {
+		actor.Dispatch(model.EvtExitRefuses{Message: message})
+		return
+	}
+
 	// actor.Room.OnExit(actor)
 
-	newRoom, err := s.GetRoom(exit.WorldID, exit.RoomID)
+	newRoom, err := s.destinationRoom(exit, key)
 	if err != nil {
-		// room doesn't exist, do something clever!
+		actor.Dispatch(model.EvtNoExitInThatDirection{})
+		return
+	}
+
+	// followers are only told they follow once they know they can
+	if leader != nil {
+		actor.Dispatch(model.EvtYouFollow{Target: leader})
 	}
 
 	// remove actor from current room
@@ -38,6 +68,8 @@ func (s *Simulation) move(actor *model.Character, c model.CommandMove) {
 		originalRoom.Dispatch(model.EvtCharacterLeaves{
 			Character: actor,
 			Direction: c.Direction,
+			Named:     c.Keyword != "",
+			Via:       exit.Name,
 		})
 	}
 
@@ -48,65 +80,131 @@ func (s *Simulation) move(actor *model.Character, c model.CommandMove) {
 
 	// tell people in the target room that a character has arrived
 	if !newRoom.Alone {
+		arrival := model.EvtCharacterArrives{
+			Character: actor,
+			Direction: c.Direction.Opposite(),
+		}
+
+		// named exits don't have an opposite, so see if there is a way back to describe instead
+		if c.Keyword != "" {
+			direction, keyword, back := newRoom.ExitLeadingTo(originalRoom.WorldID, originalRoom.ID)
+			arrival.Direction = direction
+			arrival.Named = back == nil || keyword != ""
+			if back != nil {
+				arrival.Via = back.Name
+			}
+		}
+
 		for _, ch := range newRoom.Characters {
 			if ch == actor {
 				continue
 			}
-			ch.Dispatch(model.EvtCharacterArrives{
-				Character: actor,
-				Direction: c.Direction.Opposite(),
-			})
+			ch.Dispatch(arrival)
 		}
 	}
 
 	actor.Room.OnEnter(actor)
+
+	// anyone following the actor comes along too
+	for _, follower := range followers(originalRoom, actor) {
+		s.moveFollowed(follower, actor, c, key)
+	}
+}
+
+func (s *Simulation) search(actor *model.Character, c model.CommandSearch) {
+	found := false
+
+	for _, direction := range model.Directions {
+		exit := actor.Room.Exits[direction]
+		if exit == nil || !exit.Hidden {
+			continue
+		}
+		exit.Hidden = false
+		found = true
+		s.dispatchToRoom(actor, model.EvtExitRevealed{Character: actor, Label: direction.String()})
+	}
+
+	for keyword, exit := range actor.Room.NamedExits {
+		if !exit.Hidden {
+			continue
+		}
+		exit.Hidden = false
+		found = true
+		s.dispatchToRoom(actor, model.EvtExitRevealed{Character: actor, Label: keyword})
+	}
+
+	if !found {
+		actor.Dispatch(model.EvtYouFindNothing{})
+	}
+
+	actor.Room.OnSearch(actor)
 }
 
 func (s *Simulation) takeItem(actor *model.Character, c model.CommandTake) {
-	actor.TakeItem(c.Item)
-	actor.Room.Container.RemoveItem(c.Item.ID)
+	// the item was found when the command was typed, it may have been taken or merged into another stack since
+	if actor.Room.Container.Items()[c.Item.ID] != c.Item || c.Item.Quantity < c.Quantity {
+		actor.Dispatch(model.EvtItemNotHere{})
+		return
+	}
+
+	// split the stack if only some of it is being taken
+	item := c.Item.Split(c.Quantity)
+	if item == c.Item {
+		actor.Room.Container.RemoveItem(c.Item.ID)
+	}
+	actor.TakeItem(item)
 
 	if actor.Room.Alone {
 		actor.Dispatch(model.EvtCharacterTakesItem{
 			Character: actor,
-			Item:      c.Item,
+			Item:      item,
 		})
 	} else {
 		actor.Room.Dispatch(model.EvtCharacterTakesItem{
 			Character: actor,
-			Item:      c.Item,
+			Item:      item,
 		})
 	}
 }
 
 func (s *Simulation) dropItem(actor *model.Character, c model.CommandDrop) {
-	actor.DropItem(c.Item)
-	actor.Room.Container.PutItem(c.Item)
+	// the item was found when the command was typed, it may have been dropped or merged into another stack since
+	if actor.Container.Items()[c.Item.ID] != c.Item || c.Item.Quantity < c.Quantity {
+		actor.Dispatch(model.EvtItemNotHere{})
+		return
+	}
+
+	// split the stack if only some of it is being dropped
+	item := c.Item.Split(c.Quantity)
+	if item == c.Item {
+		actor.DropItem(c.Item)
+	}
+	actor.Room.Container.PutItem(item)
 
 	if actor.Room.Alone {
 		actor.Dispatch(model.EvtCharacterDropsItem{
 			Character: actor,
-			Item:      c.Item,
+			Item:      item,
 		})
 	} else {
 		actor.Room.Dispatch(model.EvtCharacterDropsItem{
 			Character: actor,
-			Item:      c.Item,
+			Item:      item,
 		})
 	}
 }
 
 func (s *Simulation) equipItem(actor *model.Character, c model.CommandEquip) {
 	actor.Container.RemoveItem(c.Item.ID)
-	oldItem, err := actor.Equip(c.Item)
+	oldItems, err := actor.Equip(c.Item)
 	if errors.Is(err, model.ErrNotEquipable) {
-		// do something
 		actor.TakeItem(c.Item)
+		actor.Dispatch(model.EvtItemNotEquipable{Item: c.Item})
+		return
 	}
 
-	// do we have an item that we replaced
-	if oldItem != nil {
-		// tell everyone that we took off an item
+	// tell everyone that we took off any items we replaced
+	for _, oldItem := range oldItems {
 		if actor.Room.Alone {
 			actor.Dispatch(model.EvtCharacterUnequipsItem{
 				Character: actor,
@@ -133,7 +231,7 @@ func (s *Simulation) equipItem(actor *model.Character, c model.CommandEquip) {
 		})
 	}
 
-	if oldItem != nil {
+	for _, oldItem := range oldItems {
 		actor.TakeItem(oldItem)
 	}
 }
@@ -157,3 +255,326 @@ func (s *Simulation) unequipItem(actor *model.Character, c model.CommandUnequip)
 
 	actor.TakeItem(c.Item)
 }
+
+func (s *Simulation) openDoor(actor *model.Character, c model.CommandOpen) {
+	door, ok := s.findDoor(actor, c.Direction)
+	if !ok {
+		return
+	}
+
+	if door.State != model.DoorStateClosed {
+		actor.Dispatch(model.EvtDoorIs{Direction: c.Direction, State: door.State})
+		return
+	}
+
+	s.setDoorState(actor.Room, c.Direction, model.DoorStateOpen)
+	s.dispatchToRoom(actor, model.EvtCharacterOpensDoor{Character: actor, Direction: c.Direction})
+}
+
+func (s *Simulation) closeDoor(actor *model.Character, c model.CommandClose) {
+	door, ok := s.findDoor(actor, c.Direction)
+	if !ok {
+		return
+	}
+
+	if door.State != model.DoorStateOpen {
+		actor.Dispatch(model.EvtDoorIs{Direction: c.Direction, State: door.State})
+		return
+	}
+
+	s.setDoorState(actor.Room, c.Direction, model.DoorStateClosed)
+	s.dispatchToRoom(actor, model.EvtCharacterClosesDoor{Character: actor, Direction: c.Direction})
+}
+
+func (s *Simulation) lockDoor(actor *model.Character, c model.CommandLock) {
+	door, ok := s.findDoor(actor, c.Direction)
+	if !ok {
+		return
+	}
+
+	if !door.Lockable() {
+		actor.Dispatch(model.EvtDoorHasNoLock{})
+		return
+	}
+
+	// doors have to be shut before they can be locked
+	if door.State != model.DoorStateClosed {
+		actor.Dispatch(model.EvtDoorIs{Direction: c.Direction, State: door.State})
+		return
+	}
+
+	if !actor.HasItemDefinition(door.KeyID) {
+		actor.Dispatch(model.EvtYouDoNotHaveTheKey{})
+		return
+	}
+
+	s.setDoorState(actor.Room, c.Direction, model.DoorStateLocked)
+	s.dispatchToRoom(actor, model.EvtCharacterLocksDoor{Character: actor, Direction: c.Direction})
+}
+
+func (s *Simulation) unlockDoor(actor *model.Character, c model.CommandUnlock) {
+	door, ok := s.findDoor(actor, c.Direction)
+	if !ok {
+		return
+	}
+
+	if !door.Lockable() {
+		actor.Dispatch(model.EvtDoorHasNoLock{})
+		return
+	}
+
+	if door.State != model.DoorStateLocked {
+		actor.Dispatch(model.EvtDoorIs{Direction: c.Direction, State: door.State})
+		return
+	}
+
+	if !actor.HasItemDefinition(door.KeyID) {
+		actor.Dispatch(model.EvtYouDoNotHaveTheKey{})
+		return
+	}
+
+	s.setDoorState(actor.Room, c.Direction, model.DoorStateClosed)
+	s.dispatchToRoom(actor, model.EvtCharacterUnlocksDoor{Character: actor, Direction: c.Direction})
+}
+
+// findDoor looks for a door in the given direction of the actor's room.
+// If there isn't one, the actor is told so and it returns false.
+func (s *Simulation) findDoor(actor *model.Character, direction model.Direction) (*model.Door, bool) {
+	exit := actor.Room.Exits[direction]
+	if exit == nil || exit.Hidden {
+		actor.Dispatch(model.EvtNoExitInThatDirection{})
+		return nil, false
+	}
+
+	if exit.Door == nil {
+		actor.Dispatch(model.EvtNoDoorInThatDirection{})
+		return nil, false
+	}
+
+	return exit.Door, true
+}
+
+// dispatchToRoom sends the event to everyone in the actor's room, or just the actor if they are alone.
+func (s *Simulation) dispatchToRoom(actor *model.Character, event interface{}) {
+	if actor.Room.Alone {
+		actor.Dispatch(event)
+	} else {
+		actor.Room.Dispatch(event)
+	}
+}
+
+func (s *Simulation) buyItem(actor *model.Character, c model.CommandBuy) {
+	shop := s.findShop(actor.Room)
+	if shop == nil {
+		actor.Dispatch(model.EvtNoShopHere{})
+		return
+	}
+
+	definition, count := shop.FindForSale(c.Alias)
+	if definition == nil {
+		actor.Dispatch(model.EvtShopDoesNotSell{Shop: shop, Alias: c.Alias})
+		return
+	}
+
+	quantity := c.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+	if quantity > count {
+		quantity = count
+	}
+
+	price := shop.BuyPrice(definition) * int64(quantity)
+	if actor.Money < price {
+		actor.Dispatch(model.EvtCannotAfford{Shop: shop, Price: price})
+		return
+	}
+
+	actor.Money -= price
+	shop.Take(definition, quantity)
+
+	for _, item := range spawnQuantity(definition, quantity) {
+		s.dispatchToRoom(actor, model.EvtCharacterBuysItem{
+			Character: actor,
+			Shop:      shop,
+			Item:      item,
+			Price:     shop.BuyPrice(definition) * int64(item.Quantity),
+		})
+		actor.TakeItem(item)
+	}
+}
+
+func (s *Simulation) sellItem(actor *model.Character, c model.CommandSell) {
+	shop := s.findShop(actor.Room)
+	if shop == nil {
+		actor.Dispatch(model.EvtNoShopHere{})
+		return
+	}
+
+	// the item was found when the command was typed, it may have been sold or dropped since
+	if actor.Container.Items()[c.Item.ID] != c.Item || c.Item.Quantity < c.Quantity {
+		actor.Dispatch(model.EvtItemNotHere{})
+		return
+	}
+
+	// shops won't buy worthless items, or containers that might have things in them
+	price := shop.SellPrice(c.Item.Definition)
+	if price <= 0 || c.Item.Container != nil {
+		actor.Dispatch(model.EvtShopWillNotBuy{Shop: shop, Item: c.Item})
+		return
+	}
+
+	item := c.Item.Split(c.Quantity)
+	if item == c.Item {
+		actor.DropItem(c.Item)
+	}
+
+	price *= int64(item.Quantity)
+	actor.Money += price
+	shop.Give(item.Definition, item.Quantity)
+
+	s.dispatchToRoom(actor, model.EvtCharacterSellsItem{
+		Character: actor,
+		Shop:      shop,
+		Item:      item,
+		Price:     price,
+	})
+}
+
+// findShop returns the shop that trades in the room, or nil if there isn't one.
+func (s *Simulation) findShop(room *model.Room) *model.Shop {
+	if room.ShopID == 0 {
+		return nil
+	}
+	return s.shops[room.ShopID]
+}
+
+func (s *Simulation) craft(actor *model.Character, c model.CommandCraft) {
+	recipe := c.Recipe
+
+	if recipe.RoomTag != "" && !actor.Room.HasTag(recipe.RoomTag) {
+		actor.Dispatch(model.EvtCraftingNeedsRoom{Recipe: recipe, Tag: recipe.RoomTag})
+		return
+	}
+
+	for _, tool := range recipe.Tools {
+		if !actor.HasItemDefinition(tool.ID) {
+			actor.Dispatch(model.EvtCraftingNeedsTool{Recipe: recipe, Tool: tool})
+			return
+		}
+	}
+
+	// check everything is there before consuming anything
+	containers := actor.Containers()
+	for _, input := range recipe.Inputs {
+		if countItems(containers, input.Definition.ID) < input.Quantity {
+			actor.Dispatch(model.EvtCraftingNeedsInput{Recipe: recipe, Input: input.Definition, Quantity: input.Quantity})
+			return
+		}
+	}
+
+	for _, input := range recipe.Inputs {
+		consumeItems(containers, input.Definition.ID, input.Quantity)
+	}
+
+	var crafted []*model.Item
+	for _, output := range recipe.Outputs {
+		crafted = append(crafted, spawnQuantity(output.Definition, output.Quantity)...)
+	}
+
+	s.dispatchToRoom(actor, model.EvtCharacterCrafts{
+		Character: actor,
+		Recipe:    recipe,
+		Items:     crafted,
+	})
+
+	for _, item := range crafted {
+		actor.TakeItem(item)
+	}
+}
+
+// talk completes any quest steps waiting on the character talking to the target, and runs the room's talk hook.
+func (s *Simulation) talk(actor *model.Character, c model.CommandTalk) {
+	// find the matching steps before the hook runs, so a script advancing the quest itself doesn't skip a step
+	type match struct {
+		quest    *model.Quest
+		progress *model.QuestProgress
+		step     int
+	}
+	var matches []match
+	for id, progress := range actor.Quests {
+		quest, ok := s.quests[id]
+		if !ok {
+			continue
+		}
+		if step := quest.CurrentStep(progress); step != nil && step.TalkedTo(c.Target, actor.Room) {
+			matches = append(matches, match{quest: quest, progress: progress, step: progress.Step})
+		}
+	}
+
+	handled := actor.Room.OnTalk(actor, c.Target)
+
+	for _, m := range matches {
+		if !m.progress.Completed && m.progress.Step == m.step {
+			s.advanceQuest(actor, m.quest, m.progress)
+		}
+	}
+
+	if !handled && len(matches) == 0 {
+		actor.Dispatch(model.EvtNobodyToTalkTo{Target: c.Target})
+	}
+}
+
+// countItems adds up how many items of the definition are in the containers, including stack sizes.
+func countItems(containers []model.Container, id model.ItemDefinitionID) int {
+	count := 0
+	for _, container := range containers {
+		for _, item := range container.Items() {
+			if item.Definition.ID == id {
+				count += item.Quantity
+			}
+		}
+	}
+	return count
+}
+
+// consumeItems removes the quantity of items of the definition from the containers, taking from stacks where it can.
+func consumeItems(containers []model.Container, id model.ItemDefinitionID, quantity int) {
+	for _, container := range containers {
+		for _, item := range container.Items() {
+			if quantity == 0 {
+				return
+			}
+			if item.Definition.ID != id {
+				continue
+			}
+
+			if item.Quantity > quantity {
+				item.Quantity -= quantity
+				return
+			}
+
+			quantity -= item.Quantity
+			container.RemoveItem(item.ID)
+		}
+	}
+}
+
+// spawnQuantity spawns a single stack of stackable items, or one item at a time for anything else.
+func spawnQuantity(definition *model.ItemDefinition, quantity int) []*model.Item {
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	if definition.Stackable {
+		item := definition.Spawn()
+		item.Quantity = quantity
+		return []*model.Item{item}
+	}
+
+	var items []*model.Item
+	for i := 0; i < quantity; i++ {
+		items = append(items, definition.Spawn())
+	}
+	return items
+}
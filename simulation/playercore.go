@@ -1,72 +1,152 @@
 package simulation
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/soupstoregames/coda-mud/simulation/model"
 	"github.com/soupstoregames/go-core/logging"
 )
 
-func (s *Simulation) QueueCommand(id model.CharacterID, command interface{}) error {
-	char, err := s.findAwakeCharacter(id)
+// QueueCommand queues a command for the simulation to run for the character.
+// The session must be the one currently playing the character, a connection that has been taken over gets ErrSessionEnded.
+func (s *Simulation) QueueCommand(id model.CharacterID, session string, command interface{}) error {
+	s.characterLock.Lock()
+	char, err := s.findSessionCharacter(id, session)
 	if err != nil {
+		s.characterLock.Unlock()
 		return err
 	}
-
-	char.Commands <- command
-
-	return nil
+	commands, done := char.Commands, char.Done()
+	s.characterLock.Unlock()
+
+	// the simulation needs the lock to read the command, so it is sent without it, giving up if the session ends first
+	select {
+	case commands <- command:
+		return nil
+	case <-done:
+		return ErrSessionEnded
+	}
 }
 
-// WakeUpCharacter make a character wake up.
+// WakeUpCharacter make a character wake up, and returns the character's events and a token for the new session.
 // It sends a room description to the waking character.
 // It sends a character waking event to the other characters in the room.
-func (s *Simulation) WakeUpCharacter(id model.CharacterID) (characterEvents <-chan interface{}, err error) {
+// A linkdead character is reconnected and sent everything they missed.
+// An awake character returns ErrCharacterAwake unless takeover is set, in which case the old session is kicked off.
+func (s *Simulation) WakeUpCharacter(id model.CharacterID, takeover bool) (characterEvents <-chan interface{}, session string, err error) {
 	s.characterLock.Lock()
 	defer s.characterLock.Unlock()
 
 	actor, ok := s.characters[id]
 	if !ok {
-		return nil, ErrCharacterNotFound
+		return nil, "", ErrCharacterNotFound
 	}
 
-	if actor.Awake {
-		return nil, ErrCharacterAwake
+	session = uuid.NewString()
+
+	switch {
+	case actor.Awake && actor.Linkdead:
+		actor.Reconnect(session)
+		actor.Dispatch(model.EvtRoomDescription{Room: actor.Room})
+		s.dispatchToOthers(actor, model.EvtCharacterReconnects{Character: actor})
+		logging.Info("Character reconnected")
+		return actor.Events, session, nil
+
+	case actor.Awake && !takeover:
+		return nil, "", ErrCharacterAwake
+
+	case actor.Awake:
+		actor.TakeOver(session, model.EvtSessionTakenOver{})
+		actor.Dispatch(model.EvtRoomDescription{Room: actor.Room})
+		logging.Info("Character taken over by a new session")
+		return actor.Events, session, nil
 	}
 
 	// wake character and send description
-	actor.WakeUp()
+	actor.WakeUp(session)
 	actor.Dispatch(model.EvtRoomDescription{Room: actor.Room})
 
 	// send character wakes up
-	wakeUpEvent := model.EvtCharacterWakesUp{Character: actor}
-	if !actor.Room.Alone {
-		for _, c := range actor.Room.Characters {
-			// ignore the character that woke up
-			if c == actor {
-				continue
-			}
-
-			c.Dispatch(wakeUpEvent)
-		}
-	}
+	s.dispatchToOthers(actor, model.EvtCharacterWakesUp{Character: actor})
 
 	actor.Room.OnWake(actor)
 
 	logging.Info("Character woke up")
 
-	return actor.Events, nil
+	return actor.Events, session, nil
+}
+
+// DisconnectCharacter marks an awake character as linkdead when their connection drops.
+// It does nothing if the session has already been taken over or the character is asleep,
+// so it is safe to call whenever a connection closes.
+func (s *Simulation) DisconnectCharacter(id model.CharacterID, session string) error {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	if actor.Session != session || actor.Linkdead {
+		return nil
+	}
+
+	actor.Disconnect(time.Now())
+	s.dispatchToOthers(actor, model.EvtCharacterLinkdead{Character: actor})
+
+	logging.Info("Character went linkdead")
+
+	return nil
+}
+
+// CharacterPlaying returns true if the character is awake and connected.
+func (s *Simulation) CharacterPlaying(id model.CharacterID) bool {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, ok := s.characters[id]
+	return ok && actor.Awake && !actor.Linkdead
+}
+
+// SetLinkdeadGracePeriod sets how long linkdead characters stay in the world before falling asleep.
+func (s *Simulation) SetLinkdeadGracePeriod(d time.Duration) {
+	s.linkdeadGracePeriod = d
+}
+
+// expireLinkdead puts characters to sleep that have been linkdead for longer than the grace period.
+func (s *Simulation) expireLinkdead(now time.Time) {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	for _, actor := range s.characters {
+		if !actor.Awake || !actor.Linkdead || now.Sub(actor.LinkdeadSince) < s.linkdeadGracePeriod {
+			continue
+		}
+
+		s.sleep(actor)
+		logging.Info("Linkdead character fell asleep")
+	}
 }
 
 // SleepCharacter sets a character to sleeping.
 // It sends a character sleeping event to all other characters in the room.
-func (s *Simulation) SleepCharacter(id model.CharacterID) error {
+func (s *Simulation) SleepCharacter(id model.CharacterID, session string) error {
 	s.characterLock.Lock()
 	defer s.characterLock.Unlock()
 
-	actor, err := s.findAwakeCharacter(id)
+	actor, err := s.findSessionCharacter(id, session)
 	if err != nil {
 		return err
 	}
 
+	s.sleep(actor)
+
+	return nil
+}
+
+func (s *Simulation) sleep(actor *model.Character) {
 	actor.Sleep()
 
 	// send character sleeps
@@ -78,11 +158,39 @@ func (s *Simulation) SleepCharacter(id model.CharacterID) error {
 	}
 
 	actor.Room.OnExit(actor)
+}
 
-	return nil
+// dispatchToOthers sends the event to everyone else in the actor's room.
+func (s *Simulation) dispatchToOthers(actor *model.Character, event interface{}) {
+	if actor.Room.Alone {
+		return
+	}
+
+	for _, c := range actor.Room.Characters {
+		// ignore the actor
+		if c == actor {
+			continue
+		}
+
+		c.Dispatch(event)
+	}
 }
 
 // this checks that the character exists in the simulation and that they are awake (connected to)
+// findSessionCharacter finds an awake character that is still being played by the session.
+func (s *Simulation) findSessionCharacter(id model.CharacterID, session string) (*model.Character, error) {
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Session != session {
+		return nil, ErrSessionEnded
+	}
+
+	return actor, nil
+}
+
 func (s *Simulation) findAwakeCharacter(id model.CharacterID) (*model.Character, error) {
 	actor, ok := s.characters[id]
 	if !ok {
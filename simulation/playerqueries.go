@@ -1,6 +1,9 @@
 package simulation
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/soupstoregames/coda-mud/simulation/model"
 )
 
@@ -15,6 +18,51 @@ func (s *Simulation) Look(id model.CharacterID) error {
 	return nil
 }
 
+// LookAt describes something the character can see, looking in order through the room's extra descriptions,
+// the items in the room, the character's inventory and rig and finally the other characters in the room.
+func (s *Simulation) LookAt(id model.CharacterID, target string) error {
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	if extra := actor.Room.FindExtra(target); extra != nil {
+		actor.Dispatch(model.EvtExtraDescription{Extra: extra})
+		return nil
+	}
+
+	if item := actor.Room.FindItem(target); item != nil {
+		actor.Dispatch(model.EvtItemDescription{Item: item})
+		return nil
+	}
+
+	if item := actor.SearchInventory(target); item != nil {
+		actor.Dispatch(model.EvtItemDescription{Item: item})
+		return nil
+	}
+
+	if item := actor.Rig.FindItem(target); item != nil {
+		actor.Dispatch(model.EvtItemDescription{Item: item})
+		return nil
+	}
+
+	if strings.EqualFold(target, "me") || strings.EqualFold(target, "self") {
+		actor.Dispatch(model.EvtCharacterDescription{Character: actor})
+		return nil
+	}
+
+	// characters in rooms where you are alone are not visible
+	if !actor.Room.Alone {
+		if ch := actor.Room.FindCharacter(target); ch != nil {
+			actor.Dispatch(model.EvtCharacterDescription{Character: ch})
+			return nil
+		}
+	}
+
+	actor.Dispatch(model.EvtNothingToLookAt{Target: target})
+	return nil
+}
+
 // Inventory lists the users inventory and items.
 func (s *Simulation) Inventory(id model.CharacterID) error {
 	actor, err := s.findAwakeCharacter(id)
@@ -25,3 +73,149 @@ func (s *Simulation) Inventory(id model.CharacterID) error {
 	actor.Dispatch(model.EvtInventoryDescription{Character: actor})
 	return nil
 }
+
+// ListShop lists everything for sale in the shop in the character's room.
+func (s *Simulation) ListShop(id model.CharacterID) error {
+	// the simulation restocks and trades with the shop while this runs on the connection
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	shop := s.findShop(actor.Room)
+	if shop == nil {
+		actor.Dispatch(model.EvtNoShopHere{})
+		return nil
+	}
+
+	actor.Dispatch(model.EvtShopList{Shop: shop, Entries: shop.List()})
+	return nil
+}
+
+// ValueItem tells the character what the shop in their room would pay for one of the item.
+func (s *Simulation) ValueItem(id model.CharacterID, item *model.Item) error {
+	// prices depend on the shop's stock, which the simulation changes
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	shop := s.findShop(actor.Room)
+	if shop == nil {
+		actor.Dispatch(model.EvtNoShopHere{})
+		return nil
+	}
+
+	price := shop.SellPrice(item.Definition)
+	if price <= 0 || item.Container != nil {
+		actor.Dispatch(model.EvtShopWillNotBuy{Shop: shop, Item: item})
+		return nil
+	}
+
+	actor.Dispatch(model.EvtItemValue{Shop: shop, Item: item, Price: price})
+	return nil
+}
+
+// QuestLog lists every quest the character has started.
+func (s *Simulation) QuestLog(id model.CharacterID) error {
+	// quest progress changes as commands are processed, and quests are replaced when the data folder is reloaded
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	var quests []*model.Quest
+	for questID := range actor.Quests {
+		if quest, ok := s.quests[questID]; ok {
+			quests = append(quests, quest)
+		}
+	}
+	sort.Slice(quests, func(i, j int) bool { return quests[i].ID < quests[j].ID })
+
+	actor.Dispatch(model.EvtQuestLog{Character: actor, Quests: quests})
+	return nil
+}
+
+// QuestDetails describes one of the character's quests and how far they have got with it.
+func (s *Simulation) QuestDetails(id model.CharacterID, name string) error {
+	// the character's progress and the quests themselves are changed by the simulation
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	for questID, progress := range actor.Quests {
+		quest, ok := s.quests[questID]
+		if ok && quest.KnownAs(name) {
+			actor.Dispatch(model.EvtQuestDetails{Quest: quest, Progress: progress})
+			return nil
+		}
+	}
+
+	return ErrQuestNotFound
+}
+
+// Who lists every character that is awake.
+func (s *Simulation) Who(id model.CharacterID) error {
+	// characters log in, fall asleep and move while this runs on the connection
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	var characters []*model.Character
+	for _, ch := range s.characters {
+		if ch.Awake {
+			characters = append(characters, ch)
+		}
+	}
+	sort.Slice(characters, func(i, j int) bool { return characters[i].Name < characters[j].Name })
+
+	actor.Dispatch(model.EvtWho{Characters: characters})
+	return nil
+}
+
+// GroupList lists the members of the character's group.
+func (s *Simulation) GroupList(id model.CharacterID) error {
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	if actor.Group == nil {
+		actor.Dispatch(model.EvtNotInGroup{})
+		return nil
+	}
+
+	actor.Dispatch(model.EvtGroupList{Group: actor.Group})
+	return nil
+}
+
+// ChannelList lists the chat channels the character has joined.
+func (s *Simulation) ChannelList(id model.CharacterID) error {
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return err
+	}
+
+	channels := make([]string, len(actor.Channels))
+	copy(channels, actor.Channels)
+
+	actor.Dispatch(model.EvtChannelList{Channels: channels})
+	return nil
+}
@@ -1,6 +1,8 @@
 package simulation
 
 import (
+	"strings"
+
 	"github.com/soupstoregames/coda-mud/simulation/model"
 )
 
@@ -42,3 +44,68 @@ func (s *Simulation) FindItemInRig(id model.CharacterID, alias string) (*model.I
 
 	return nil, ErrItemNotFound
 }
+
+// FindNamedExit matches text against the named exits in the character's room and returns the exit's keyword.
+func (s *Simulation) FindNamedExit(id model.CharacterID, text string) (string, error) {
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return "", err
+	}
+
+	if exit := actor.Room.FindNamedExit(text); exit != nil {
+		return exit.Keyword, nil
+	}
+
+	return "", ErrExitNotFound
+}
+
+// FindRecipe returns the crafting recipe known by the alias.
+func (s *Simulation) FindRecipe(id model.CharacterID, alias string) (*model.Recipe, error) {
+	// recipes are replaced by the simulation when the data folder is reloaded
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	if _, err := s.findAwakeCharacter(id); err != nil {
+		return nil, err
+	}
+
+	for _, recipe := range s.recipes {
+		if recipe.KnownAs(alias) {
+			return recipe, nil
+		}
+	}
+
+	return nil, ErrRecipeNotFound
+}
+
+// InChannel returns true if the character has joined the chat channel.
+func (s *Simulation) InChannel(id model.CharacterID, channel string) bool {
+	// the simulation changes the character's channels as they join and leave them
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	actor, err := s.findAwakeCharacter(id)
+	if err != nil {
+		return false
+	}
+
+	return actor.InChannel(channel)
+}
+
+// FindSocial returns the social with the name.
+func (s *Simulation) FindSocial(id model.CharacterID, name string) (*model.Social, error) {
+	// socials are replaced by the simulation when the data folder is reloaded
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	if _, err := s.findAwakeCharacter(id); err != nil {
+		return nil, err
+	}
+
+	social, ok := s.socials[strings.ToLower(name)]
+	if !ok {
+		return nil, ErrSocialNotFound
+	}
+
+	return social, nil
+}
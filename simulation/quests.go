@@ -0,0 +1,178 @@
+package simulation
+
+import (
+	"github.com/soupstoregames/coda-mud/simulation/model"
+)
+
+// StartQuest gives the character the quest, it does nothing if they have already started it.
+// It is used by room scripts.
+func (s *Simulation) StartQuest(characterID model.CharacterID, questID model.QuestID) error {
+	character, quest, err := s.findQuest(characterID, questID)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := character.Quests[questID]; ok {
+		return nil
+	}
+
+	character.Quests[questID] = &model.QuestProgress{}
+	character.Dispatch(model.EvtQuestStarted{Quest: quest})
+
+	return nil
+}
+
+// AdvanceQuest moves the character on to the next step of the quest, whether or not they have finished the current one.
+// It is used by room scripts.
+func (s *Simulation) AdvanceQuest(characterID model.CharacterID, questID model.QuestID) error {
+	character, quest, err := s.findQuest(characterID, questID)
+	if err != nil {
+		return err
+	}
+
+	progress, ok := character.Quests[questID]
+	if !ok {
+		return ErrQuestNotStarted
+	}
+
+	if !progress.Completed {
+		s.advanceQuest(character, quest, progress)
+	}
+
+	return nil
+}
+
+// CompleteQuest finishes the quest for the character, skipping any steps that are left, and hands out the rewards.
+// It is used by room scripts.
+func (s *Simulation) CompleteQuest(characterID model.CharacterID, questID model.QuestID) error {
+	character, quest, err := s.findQuest(characterID, questID)
+	if err != nil {
+		return err
+	}
+
+	progress, ok := character.Quests[questID]
+	if !ok {
+		return ErrQuestNotStarted
+	}
+
+	if !progress.Completed {
+		s.completeQuest(character, quest, progress)
+	}
+
+	return nil
+}
+
+// SetFlag sets or clears a named flag on the character.
+// It is used by room scripts.
+func (s *Simulation) SetFlag(characterID model.CharacterID, flag string, value bool) error {
+	character, ok := s.characters[characterID]
+	if !ok {
+		return ErrCharacterNotFound
+	}
+
+	if value {
+		character.Flags[flag] = true
+	} else {
+		delete(character.Flags, flag)
+	}
+
+	return nil
+}
+
+// HasFlag returns true if the flag is set on the character.
+// It is used by room scripts.
+func (s *Simulation) HasFlag(characterID model.CharacterID, flag string) bool {
+	character, ok := s.characters[characterID]
+	if !ok {
+		return false
+	}
+
+	return character.Flags[flag]
+}
+
+func (s *Simulation) findQuest(characterID model.CharacterID, questID model.QuestID) (*model.Character, *model.Quest, error) {
+	character, ok := s.characters[characterID]
+	if !ok {
+		return nil, nil, ErrCharacterNotFound
+	}
+
+	quest, ok := s.quests[questID]
+	if !ok {
+		return nil, nil, ErrQuestNotFound
+	}
+
+	return character, quest, nil
+}
+
+// advanceQuest assumes that the quest has not been completed.
+func (s *Simulation) advanceQuest(character *model.Character, quest *model.Quest, progress *model.QuestProgress) {
+	progress.Step++
+
+	step := quest.CurrentStep(progress)
+	if step == nil {
+		s.completeQuest(character, quest, progress)
+		return
+	}
+
+	character.Dispatch(model.EvtQuestAdvanced{Quest: quest, Step: step})
+}
+
+// completeQuest assumes that the quest has not been completed.
+// Reward items go into the character's inventory.
+func (s *Simulation) completeQuest(character *model.Character, quest *model.Quest, progress *model.QuestProgress) {
+	progress.Step = len(quest.Steps)
+	progress.Completed = true
+
+	var rewards []*model.Item
+	for _, reward := range quest.RewardItems {
+		rewards = append(rewards, spawnQuantity(reward.Definition, reward.Quantity)...)
+	}
+
+	character.Money += quest.RewardMoney
+	for _, item := range rewards {
+		character.TakeItem(item)
+	}
+
+	character.Dispatch(model.EvtQuestCompleted{
+		Quest: quest,
+		Money: quest.RewardMoney,
+		Items: rewards,
+	})
+}
+
+// progressQuests checks the current step of every quest the character is on, and advances past any that are done.
+// Talk steps are only completed by the talk action.
+func (s *Simulation) progressQuests(character *model.Character) {
+	for id, progress := range character.Quests {
+		quest, ok := s.quests[id]
+		if !ok {
+			continue
+		}
+
+		for step := quest.CurrentStep(progress); step != nil && s.stepDone(character, step); step = quest.CurrentStep(progress) {
+			s.advanceQuest(character, quest, progress)
+		}
+	}
+}
+
+func (s *Simulation) stepDone(character *model.Character, step *model.QuestStep) bool {
+	switch step.Type {
+	case model.QuestStepVisit:
+		// steps name the instancable world, not the copy of it the character is in
+		worldID := character.Room.WorldID
+		if world, ok := s.worlds[worldID]; ok && world.Instance {
+			worldID = world.Template
+		}
+		return worldID == step.WorldID && character.Room.ID == step.RoomID
+	case model.QuestStepObtain:
+		quantity := step.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		return countItems(character.Containers(), step.Item.ID) >= quantity
+	case model.QuestStepFlag:
+		return character.Flags[step.Flag]
+	}
+
+	return false
+}
@@ -1,27 +1,173 @@
 package simulation
 
 import (
+	"sort"
+	"strings"
+	"unicode"
+
 	"github.com/soupstoregames/coda-mud/simulation/model"
 )
 
 // RegistrationController is an interface on Simulation that can be used to create
 // new character for new accounts
 type RegistrationController interface {
-	MakeCharacter(name string) model.CharacterID
+	MakeCharacter(name, description string, attributes model.Attributes, backgroundID model.BackgroundID) (model.CharacterID, error)
+	ValidateCharacterName(name string) error
+	Backgrounds() []*model.Background
+	DeleteCharacter(id model.CharacterID) error
+	CharacterSummaries(ids []model.CharacterID) []CharacterSummary
+}
+
+// CharacterSummary is what an account's character list shows about each character.
+type CharacterSummary struct {
+	ID      model.CharacterID
+	Name    string
+	Room    string
+	Region  string
+	Playing bool
 }
 
 // MakeCharacter creates a new character at the next available ID
+// The character starts with the money and items from their background, if they chose one.
 // It returns the new character's ID
-func (s *Simulation) MakeCharacter(name string) model.CharacterID {
+func (s *Simulation) MakeCharacter(name, description string, attributes model.Attributes, backgroundID model.BackgroundID) (model.CharacterID, error) {
 	s.characterLock.Lock()
 	defer s.characterLock.Unlock()
-	
+
+	if err := s.validateCharacterName(name); err != nil {
+		return "", err
+	}
+
+	var background *model.Background
+	if backgroundID != 0 {
+		var ok bool
+		if background, ok = s.backgrounds[backgroundID]; !ok {
+			return "", ErrBackgroundNotFound
+		}
+	}
+
 	// create new character and add to sim
 	character := model.NewCharacter(name, s.spawnRoom)
+	character.Description = description
+	character.Attributes = attributes
+	character.Background = backgroundID
 	s.characters[character.ID] = character
 
+	// hand out the starting kit
+	if background != nil {
+		character.Money = background.Money
+		for _, kit := range background.Kit {
+			for _, item := range spawnQuantity(kit.Definition, kit.Quantity) {
+				character.TakeItem(item)
+			}
+		}
+	}
+
 	// add character to room
 	s.spawnRoom.AddCharacter(character)
 
-	return character.ID
+	return character.ID, nil
+}
+
+// reservedNames can't be used as character names because they would be confused with commands or targets.
+var reservedNames = []string{
+	"admin", "all", "builder", "everyone", "god", "me", "moderator", "nobody", "self", "someone", "system", "you",
+}
+
+// ValidateCharacterName checks that a name is long enough, only uses letters, isn't reserved and isn't already taken.
+func (s *Simulation) ValidateCharacterName(name string) error {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	return s.validateCharacterName(name)
+}
+
+func (s *Simulation) validateCharacterName(name string) error {
+	if len(name) < 3 || len(name) > 16 {
+		return ErrCharacterNameLength
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) || r > unicode.MaxASCII {
+			return ErrCharacterNameInvalid
+		}
+	}
+
+	for _, reserved := range reservedNames {
+		if strings.EqualFold(name, reserved) {
+			return ErrCharacterNameReserved
+		}
+	}
+
+	for _, character := range s.characters {
+		if strings.EqualFold(character.Name, name) {
+			return ErrCharacterNameTaken
+		}
+	}
+
+	return nil
+}
+
+// Backgrounds returns every background players can choose from, in ID order.
+func (s *Simulation) Backgrounds() []*model.Background {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	var backgrounds []*model.Background
+	for _, background := range s.backgrounds {
+		backgrounds = append(backgrounds, background)
+	}
+	sort.Slice(backgrounds, func(i, j int) bool { return backgrounds[i].ID < backgrounds[j].ID })
+
+	return backgrounds
+}
+
+// DeleteCharacter removes a sleeping character from the simulation, along with everything they carry.
+// Their saved state is deleted the next time the simulation is saved.
+func (s *Simulation) DeleteCharacter(id model.CharacterID) error {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	character, ok := s.characters[id]
+	if !ok {
+		return ErrCharacterNotFound
+	}
+
+	if character.Awake {
+		return ErrCharacterAwake
+	}
+
+	if character.Group != nil {
+		s.removeFromGroup(character, false)
+	}
+
+	character.Room.RemoveCharacter(character)
+	delete(s.characters, id)
+	s.deletedCharacters = append(s.deletedCharacters, id)
+
+	return nil
+}
+
+// CharacterSummaries describes each of the characters, skipping any that don't exist.
+func (s *Simulation) CharacterSummaries(ids []model.CharacterID) []CharacterSummary {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	var summaries []CharacterSummary
+	for _, id := range ids {
+		character, ok := s.characters[id]
+		if !ok {
+			continue
+		}
+
+		summaries = append(summaries, CharacterSummary{
+			ID:      character.ID,
+			Name:    character.Name,
+			Room:    character.Room.Name,
+			Region:  character.Room.Region,
+			Playing: character.Awake && !character.Linkdead,
+		})
+	}
+
+	return summaries
 }
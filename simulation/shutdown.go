@@ -0,0 +1,114 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
+	"github.com/soupstoregames/go-core/logging"
+)
+
+// shutdownWarnings are how long before a scheduled shutdown players are reminded that it is coming.
+var shutdownWarnings = []time.Duration{
+	10 * time.Minute,
+	5 * time.Minute,
+	2 * time.Minute,
+	time.Minute,
+	30 * time.Second,
+	10 * time.Second,
+}
+
+// ShutdownRequested is closed when an admin's scheduled shutdown is due.
+func (s *Simulation) ShutdownRequested() <-chan struct{} {
+	return s.shutdownRequested
+}
+
+// Shutdown tells everyone playing that the server is stopping, then puts every awake character to sleep.
+// Characters are left where they are so that they can be saved afterwards.
+func (s *Simulation) Shutdown() {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	s.broadcast(model.EvtServerShuttingDown{})
+
+	for _, actor := range s.characters {
+		if !actor.Awake {
+			continue
+		}
+
+		actor.Sleep()
+		actor.Room.OnExit(actor)
+	}
+
+	logging.Info("All characters put to sleep for shutdown")
+}
+
+// scheduleShutdown starts counting down to a shutdown, replacing any shutdown that was already scheduled.
+func (s *Simulation) scheduleShutdown(actor *model.Character, c model.CommandAdminShutdown) {
+	if s.pendingShutdown != nil {
+		close(s.pendingShutdown)
+	}
+
+	cancel := make(chan struct{})
+	s.pendingShutdown = cancel
+
+	s.broadcast(model.EvtShutdownScheduled{Character: actor, In: c.Delay})
+	logging.Info(fmt.Sprintf("%s scheduled a shutdown in %v", actor.Name, c.Delay))
+
+	go s.countDownToShutdown(time.Now().Add(c.Delay), cancel)
+}
+
+func (s *Simulation) cancelShutdown(actor *model.Character, c model.CommandAdminShutdownCancel) {
+	if s.pendingShutdown == nil {
+		actor.Dispatch(model.EvtNoShutdownScheduled{})
+		return
+	}
+
+	close(s.pendingShutdown)
+	s.pendingShutdown = nil
+
+	s.broadcast(model.EvtShutdownCancelled{Character: actor})
+	logging.Info(fmt.Sprintf("%s cancelled the shutdown", actor.Name))
+}
+
+// countDownToShutdown warns everyone as the deadline gets closer, then requests the shutdown.
+// It stops early if the cancel channel is closed.
+func (s *Simulation) countDownToShutdown(deadline time.Time, cancel chan struct{}) {
+	for _, warning := range shutdownWarnings {
+		wait := time.Until(deadline.Add(-warning))
+		if wait <= 0 {
+			continue
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-cancel:
+			return
+		}
+
+		s.characterLock.Lock()
+		if s.pendingShutdown == cancel {
+			s.broadcast(model.EvtShutdownCountdown{In: warning})
+		}
+		s.characterLock.Unlock()
+	}
+
+	select {
+	case <-time.After(time.Until(deadline)):
+	case <-cancel:
+		return
+	}
+
+	s.requestShutdown.Do(func() {
+		close(s.shutdownRequested)
+	})
+}
+
+// broadcast sends the event to every awake character in the simulation.
+func (s *Simulation) broadcast(event interface{}) {
+	for _, ch := range s.characters {
+		if ch.Awake {
+			ch.Dispatch(event)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package simulation
 
 import (
-	"github.com/soupstoregames/coda-mud/simulation/model"
 	"sync"
+	"time"
+
+	"github.com/soupstoregames/coda-mud/simulation/model"
 )
 
 // Simulation is the engine of the world.
@@ -15,6 +17,29 @@ type Simulation struct {
 	items           map[model.ItemID]*model.Item
 	characters      map[model.CharacterID]*model.Character
 	containers      map[model.ContainerID]model.Container
+	shops           map[model.ShopID]*model.Shop
+	recipes         map[model.RecipeID]*model.Recipe
+	quests          map[model.QuestID]*model.Quest
+	groups          map[model.GroupID]*model.Group
+	channels        map[string]*model.Channel
+	socials         map[string]*model.Social
+	backgrounds     map[model.BackgroundID]*model.Background
+
+	// deletedCharacters are removed from the saved state the next time the simulation is saved
+	deletedCharacters []model.CharacterID
+
+	// linkdeadGracePeriod is how long characters that lose their connection stay in the world
+	linkdeadGracePeriod time.Duration
+
+	// dataWriter saves changes made by builders, building is disabled without one
+	dataWriter DataWriter
+	// moderators says which characters staff outrank
+	moderators Moderators
+
+	// pendingShutdown is closed to cancel the shutdown an admin scheduled, it is nil if there isn't one
+	pendingShutdown   chan struct{}
+	shutdownRequested chan struct{}
+	requestShutdown   sync.Once
 
 	characterLock *sync.Mutex
 }
@@ -28,6 +53,17 @@ func NewSimulation() *Simulation {
 		items:           make(map[model.ItemID]*model.Item),
 		characters:      make(map[model.CharacterID]*model.Character),
 		containers:      make(map[model.ContainerID]model.Container),
+		shops:           make(map[model.ShopID]*model.Shop),
+		recipes:         make(map[model.RecipeID]*model.Recipe),
+		quests:          make(map[model.QuestID]*model.Quest),
+		groups:          make(map[model.GroupID]*model.Group),
+		channels:        make(map[string]*model.Channel),
+		socials:         make(map[string]*model.Social),
+		backgrounds:     make(map[model.BackgroundID]*model.Background),
+
+		linkdeadGracePeriod: 5 * time.Minute,
+
+		shutdownRequested: make(chan struct{}),
 
 		characterLock: &sync.Mutex{},
 	}
@@ -39,6 +75,24 @@ func (s *Simulation) Start() {
 			s.processPlayerCommands()
 		}
 	}()
+
+	t := time.NewTicker(10 * time.Second)
+	go func() {
+		for now := range t.C {
+			s.restockShops(now)
+			s.expireLinkdead(now)
+			s.expireInstances(now)
+		}
+	}()
+}
+
+func (s *Simulation) restockShops(now time.Time) {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	for _, shop := range s.shops {
+		shop.Restock(now)
+	}
 }
 
 func (s *Simulation) processPlayerCommands() {
@@ -58,6 +112,8 @@ func (s *Simulation) processPlayerCommands() {
 				s.say(c, v)
 			case model.CommandMove:
 				s.move(c, v)
+			case model.CommandSearch:
+				s.search(c, v)
 			case model.CommandTake:
 				s.takeItem(c, v)
 			case model.CommandDrop:
@@ -66,7 +122,91 @@ func (s *Simulation) processPlayerCommands() {
 				s.equipItem(c, v)
 			case model.CommandUnequip:
 				s.unequipItem(c, v)
+			case model.CommandOpen:
+				s.openDoor(c, v)
+			case model.CommandClose:
+				s.closeDoor(c, v)
+			case model.CommandLock:
+				s.lockDoor(c, v)
+			case model.CommandUnlock:
+				s.unlockDoor(c, v)
+			case model.CommandBuy:
+				s.buyItem(c, v)
+			case model.CommandSell:
+				s.sellItem(c, v)
+			case model.CommandCraft:
+				s.craft(c, v)
+			case model.CommandTalk:
+				s.talk(c, v)
+			case model.CommandFollow:
+				s.follow(c, v)
+			case model.CommandGroupInvite:
+				s.inviteToGroup(c, v)
+			case model.CommandGroupAccept:
+				s.acceptGroupInvite(c, v)
+			case model.CommandGroupLeave:
+				s.leaveGroup(c, v)
+			case model.CommandGroupKick:
+				s.kickFromGroup(c, v)
+			case model.CommandPartyChat:
+				s.partyChat(c, v)
+			case model.CommandTell:
+				s.tell(c, v)
+			case model.CommandReply:
+				s.reply(c, v)
+			case model.CommandWhisper:
+				s.whisper(c, v)
+			case model.CommandShout:
+				s.shout(c, v)
+			case model.CommandEmote:
+				s.emote(c, v)
+			case model.CommandChannelJoin:
+				s.joinChannel(c, v)
+			case model.CommandChannelLeave:
+				s.leaveChannel(c, v)
+			case model.CommandChannelSay:
+				s.channelSay(c, v)
+			case model.CommandSocial:
+				s.social(c, v)
+			case model.CommandAdminGoto:
+				s.adminGoto(c, v)
+			case model.CommandAdminSummon:
+				s.adminSummon(c, v)
+			case model.CommandAdminTeleport:
+				s.adminTeleport(c, v)
+			case model.CommandAdminKick:
+				s.adminKick(c, v)
+			case model.CommandAdminInspect:
+				s.adminInspect(c, v)
+			case model.CommandAdminSet:
+				s.adminSet(c, v)
+			case model.CommandAdminPurge:
+				s.adminPurge(c, v)
+			case model.CommandBuildDig:
+				s.dig(c, v)
+			case model.CommandBuildRename:
+				s.renameRoom(c, v)
+			case model.CommandBuildDescribe:
+				s.describeRoom(c, v)
+			case model.CommandBuildRegion:
+				s.setRoomRegion(c, v)
+			case model.CommandBuildExitLink:
+				s.linkExit(c, v)
+			case model.CommandBuildExitUnlink:
+				s.unlinkExit(c, v)
+			case model.CommandBuildItemDefinitionCreate:
+				s.createItemDefinition(c, v)
+			case model.CommandBuildItemDefinitionEdit:
+				s.editItemDefinition(c, v)
+			case model.CommandBuildReload:
+				s.reloadData(c, v)
+			case model.CommandAdminShutdown:
+				s.scheduleShutdown(c, v)
+			case model.CommandAdminShutdownCancel:
+				s.cancelShutdown(c, v)
 			}
+
+			s.progressQuests(c)
 		default:
 			continue
 		}
@@ -1,6 +1,11 @@
 package simulation
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/soupstoregames/coda-mud/simulation/model"
 )
 
@@ -8,12 +13,28 @@ import (
 type WorldController interface {
 	CreateWorld(worldID model.WorldID, instance bool, alone bool) error
 	DestroyWorld(worldID model.WorldID)
+	GetWorld(worldID model.WorldID) (*model.World, error)
+	GetWorlds() []*model.World
 	CreateRoom(worldID model.WorldID, roomID model.RoomID, name, region, description, script string) (*model.Room, error)
 	GetRoom(worldID model.WorldID, roomID model.RoomID) (*model.Room, error)
 	DestroyRoom(worldID model.WorldID, roomID model.RoomID) error
 	SetSpawnRoom(worldID model.WorldID, roomID model.RoomID) error
-	CreateItemDefinition(itemID model.ItemDefinitionID, name string, aliases []string, weight int64, rigSlot model.RigSlot, container *model.ContainerDefinition) (*model.ItemDefinition, error)
+	CreateItemDefinition(itemID model.ItemDefinitionID, name string, aliases []string, weight int64, rigSlot model.RigSlot, extraRigSlots []model.RigSlot, container *model.ContainerDefinition) (*model.ItemDefinition, error)
+	GetItemDefinition(itemID model.ItemDefinitionID) (*model.ItemDefinition, error)
+	ItemDefinitionUses(itemID model.ItemDefinitionID) (ItemDefinitionUses, error)
+	DestroyItemDefinition(itemID model.ItemDefinitionID) (ItemDefinitionUses, error)
 	SpawnItem(itemDefinitionID model.ItemDefinitionID, containerID model.ContainerID) error
+	CreateShop(shopID model.ShopID, name, keeper string, buyMultiplier, sellMultiplier float64, restockInterval time.Duration, stock map[model.ItemDefinitionID]int) (*model.Shop, error)
+	DestroyShop(shopID model.ShopID)
+	CreateRecipe(recipeID model.RecipeID, name string, aliases []string, roomTag string, inputs map[model.ItemDefinitionID]int, tools []model.ItemDefinitionID, outputs map[model.ItemDefinitionID]int) (*model.Recipe, error)
+	DestroyRecipe(recipeID model.RecipeID)
+	CreateQuest(questID model.QuestID, name, description string, steps []*model.QuestStep, rewardMoney int64, rewardItems map[model.ItemDefinitionID]int) (*model.Quest, error)
+	DestroyQuest(questID model.QuestID)
+	CreateSocial(name string, untargeted, targeted, self model.SocialMessages) *model.Social
+	DestroySocial(name string)
+	CreateBackground(backgroundID model.BackgroundID, name, description string, money int64, kit map[model.ItemDefinitionID]int) (*model.Background, error)
+	DestroyBackground(backgroundID model.BackgroundID)
+	Update(changes func())
 }
 
 // CreateWorld creates a new world in the simulation.
@@ -30,6 +51,33 @@ func (s *Simulation) DestroyWorld(worldID model.WorldID) {
 	delete(s.worlds, worldID)
 }
 
+// GetWorld returns the world with the ID, so that its details can be set after it is created.
+func (s *Simulation) GetWorld(worldID model.WorldID) (*model.World, error) {
+	world, ok := s.worlds[worldID]
+	if !ok {
+		return nil, ErrWorldNotFound
+	}
+	return world, nil
+}
+
+// GetWorlds returns every world in the simulation, including instances.
+func (s *Simulation) GetWorlds() []*model.World {
+	worlds := make([]*model.World, 0, len(s.worlds))
+	for _, world := range s.worlds {
+		worlds = append(worlds, world)
+	}
+	return worlds
+}
+
+// Update makes changes to the world while no commands are being run, so that players see all of the changes at once.
+// It must not be called from inside a command, which already holds the simulation still.
+func (s *Simulation) Update(changes func()) {
+	s.characterLock.Lock()
+	defer s.characterLock.Unlock()
+
+	changes()
+}
+
 // CreateRoom creates a new room in the specified world with the specified room ID
 func (s *Simulation) CreateRoom(worldID model.WorldID, roomID model.RoomID, name, region, description, script string) (*model.Room, error) {
 	world, ok := s.worlds[worldID]
@@ -40,6 +88,7 @@ func (s *Simulation) CreateRoom(worldID model.WorldID, roomID model.RoomID, name
 	// TODO: Check that room with ID does not already exist
 
 	room := model.NewRoom(roomID, worldID, name, region, description, script, world.Alone)
+	room.Host = s
 	world.Rooms[roomID] = room
 
 	container := room.Container
@@ -90,12 +139,188 @@ func (s *Simulation) SetSpawnRoom(worldID model.WorldID, roomID model.RoomID) er
 }
 
 // CreateItemDefinition creates a new item definition
-func (s *Simulation) CreateItemDefinition(itemID model.ItemDefinitionID, name string, aliases []string, weight int64, rigSlot model.RigSlot, container *model.ContainerDefinition) (*model.ItemDefinition, error) {
-	item := model.NewItemDefinition(itemID, name, aliases, weight, rigSlot, container)
+func (s *Simulation) CreateItemDefinition(itemID model.ItemDefinitionID, name string, aliases []string, weight int64, rigSlot model.RigSlot, extraRigSlots []model.RigSlot, container *model.ContainerDefinition) (*model.ItemDefinition, error) {
+	item := model.NewItemDefinition(itemID, name, aliases, weight, rigSlot, extraRigSlots, container)
 	s.itemDefinitions[itemID] = item
 	return item, nil
 }
 
+// GetItemDefinition returns the item definition with the ID.
+func (s *Simulation) GetItemDefinition(itemID model.ItemDefinitionID) (*model.ItemDefinition, error) {
+	definition, ok := s.itemDefinitions[itemID]
+	if !ok {
+		return nil, ErrItemDefinitionNotFound
+	}
+	return definition, nil
+}
+
+// ItemDefinitionUses are the things in the simulation that still need an item definition.
+type ItemDefinitionUses struct {
+	// Items is how many items in the world are made from the definition
+	Items       int
+	Shops       []model.ShopID
+	Recipes     []model.RecipeID
+	Quests      []model.QuestID
+	Backgrounds []model.BackgroundID
+}
+
+// InUse returns true if anything still needs the definition.
+func (u ItemDefinitionUses) InUse() bool {
+	return u.Items > 0 || len(u.Shops) > 0 || len(u.Recipes) > 0 || len(u.Quests) > 0 || len(u.Backgrounds) > 0
+}
+
+func (u ItemDefinitionUses) String() string {
+	var uses []string
+	if u.Items > 0 {
+		uses = append(uses, fmt.Sprintf("%d items in the world", u.Items))
+	}
+	for _, id := range u.Shops {
+		uses = append(uses, fmt.Sprintf("shop %d", id))
+	}
+	for _, id := range u.Recipes {
+		uses = append(uses, fmt.Sprintf("recipe %d", id))
+	}
+	for _, id := range u.Quests {
+		uses = append(uses, fmt.Sprintf("quest %d", id))
+	}
+	for _, id := range u.Backgrounds {
+		uses = append(uses, fmt.Sprintf("background %d", id))
+	}
+	return strings.Join(uses, ", ")
+}
+
+// ItemDefinitionUses finds everything that still needs the item definition:
+// items made from it, and the shops, recipes, quests and backgrounds that refer to it.
+func (s *Simulation) ItemDefinitionUses(itemID model.ItemDefinitionID) (ItemDefinitionUses, error) {
+	definition, ok := s.itemDefinitions[itemID]
+	if !ok {
+		return ItemDefinitionUses{}, ErrItemDefinitionNotFound
+	}
+
+	uses := ItemDefinitionUses{Items: s.countSpawnedItems(definition)}
+
+	for id, shop := range s.shops {
+		if shop.Trades(itemID) {
+			uses.Shops = append(uses.Shops, id)
+		}
+	}
+
+	for id, recipe := range s.recipes {
+		if recipeUses(recipe, definition) {
+			uses.Recipes = append(uses.Recipes, id)
+		}
+	}
+
+	for id, quest := range s.quests {
+		if questUses(quest, definition) {
+			uses.Quests = append(uses.Quests, id)
+		}
+	}
+
+	for id, background := range s.backgrounds {
+		for _, item := range background.Kit {
+			if item.Definition == definition {
+				uses.Backgrounds = append(uses.Backgrounds, id)
+				break
+			}
+		}
+	}
+
+	sort.Slice(uses.Shops, func(i, j int) bool { return uses.Shops[i] < uses.Shops[j] })
+	sort.Slice(uses.Recipes, func(i, j int) bool { return uses.Recipes[i] < uses.Recipes[j] })
+	sort.Slice(uses.Quests, func(i, j int) bool { return uses.Quests[i] < uses.Quests[j] })
+	sort.Slice(uses.Backgrounds, func(i, j int) bool { return uses.Backgrounds[i] < uses.Backgrounds[j] })
+
+	return uses, nil
+}
+
+func recipeUses(recipe *model.Recipe, definition *model.ItemDefinition) bool {
+	for _, input := range recipe.Inputs {
+		if input.Definition == definition {
+			return true
+		}
+	}
+	for _, tool := range recipe.Tools {
+		if tool == definition {
+			return true
+		}
+	}
+	for _, output := range recipe.Outputs {
+		if output.Definition == definition {
+			return true
+		}
+	}
+	return false
+}
+
+func questUses(quest *model.Quest, definition *model.ItemDefinition) bool {
+	for _, step := range quest.Steps {
+		if step.Item == definition {
+			return true
+		}
+	}
+	for _, reward := range quest.RewardItems {
+		if reward.Definition == definition {
+			return true
+		}
+	}
+	return false
+}
+
+// DestroyItemDefinition removes an item definition so no more items can be made from it.
+// It refuses with ErrItemDefinitionInUse if anything still needs the definition, and returns what does.
+func (s *Simulation) DestroyItemDefinition(itemID model.ItemDefinitionID) (ItemDefinitionUses, error) {
+	uses, err := s.ItemDefinitionUses(itemID)
+	if err != nil {
+		return uses, err
+	}
+
+	if uses.InUse() {
+		return uses, ErrItemDefinitionInUse
+	}
+
+	delete(s.itemDefinitions, itemID)
+	return uses, nil
+}
+
+// countSpawnedItems counts the items of the definition anywhere in the world: on floors, carried, equipped or inside other items.
+func (s *Simulation) countSpawnedItems(definition *model.ItemDefinition) int {
+	count := 0
+	for _, world := range s.worlds {
+		for _, room := range world.Rooms {
+			count += countSpawnedIn(room.Container, definition)
+		}
+	}
+
+	for _, character := range s.characters {
+		count += countSpawnedIn(character.Container, definition)
+		for _, item := range character.Rig.Items() {
+			count += countSpawned(item, definition)
+		}
+	}
+
+	return count
+}
+
+func countSpawnedIn(container model.Container, definition *model.ItemDefinition) int {
+	count := 0
+	for _, item := range container.Items() {
+		count += countSpawned(item, definition)
+	}
+	return count
+}
+
+func countSpawned(item *model.Item, definition *model.ItemDefinition) int {
+	count := 0
+	if item.Definition == definition {
+		count++
+	}
+	if item.Container != nil {
+		count += countSpawnedIn(item.Container, definition)
+	}
+	return count
+}
+
 // SpawnItem creates a new instance of the item definition in the desired container.
 func (s *Simulation) SpawnItem(itemDefinitionID model.ItemDefinitionID, containerID model.ContainerID) error {
 	container, ok := s.containers[containerID]
@@ -112,3 +337,139 @@ func (s *Simulation) SpawnItem(itemDefinitionID model.ItemDefinitionID, containe
 
 	return nil
 }
+
+// CreateShop creates a new shop, stocked with the given number of each item definition.
+// It replaces any shop that already has the ID, along with everything that was sold to it.
+func (s *Simulation) CreateShop(shopID model.ShopID, name, keeper string, buyMultiplier, sellMultiplier float64, restockInterval time.Duration, stock map[model.ItemDefinitionID]int) (*model.Shop, error) {
+	shop := model.NewShop(shopID, name, keeper, buyMultiplier, sellMultiplier, restockInterval)
+
+	for id, quantity := range stock {
+		definition, ok := s.itemDefinitions[id]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		shop.AddStock(definition, quantity)
+	}
+
+	s.shops[shopID] = shop
+	return shop, nil
+}
+
+// DestroyShop removes a shop. Rooms that had it no longer have a shop.
+func (s *Simulation) DestroyShop(shopID model.ShopID) {
+	delete(s.shops, shopID)
+}
+
+// CreateRecipe creates a crafting recipe, replacing any recipe that already has the ID.
+func (s *Simulation) CreateRecipe(recipeID model.RecipeID, name string, aliases []string, roomTag string, inputs map[model.ItemDefinitionID]int, tools []model.ItemDefinitionID, outputs map[model.ItemDefinitionID]int) (*model.Recipe, error) {
+	recipe := model.NewRecipe(recipeID, name, aliases, roomTag)
+
+	for id, quantity := range inputs {
+		definition, ok := s.itemDefinitions[id]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		recipe.Inputs = append(recipe.Inputs, model.RecipeComponent{Definition: definition, Quantity: quantity})
+	}
+
+	for _, id := range tools {
+		definition, ok := s.itemDefinitions[id]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		recipe.Tools = append(recipe.Tools, definition)
+	}
+
+	for id, quantity := range outputs {
+		definition, ok := s.itemDefinitions[id]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		recipe.Outputs = append(recipe.Outputs, model.RecipeComponent{Definition: definition, Quantity: quantity})
+	}
+
+	s.recipes[recipeID] = recipe
+	return recipe, nil
+}
+
+// DestroyRecipe removes a crafting recipe.
+func (s *Simulation) DestroyRecipe(recipeID model.RecipeID) {
+	delete(s.recipes, recipeID)
+}
+
+// CreateQuest creates a quest, replacing any quest that already has the ID.
+// Steps that need an item have it looked up from the step's ItemID.
+func (s *Simulation) CreateQuest(questID model.QuestID, name, description string, steps []*model.QuestStep, rewardMoney int64, rewardItems map[model.ItemDefinitionID]int) (*model.Quest, error) {
+	quest := &model.Quest{
+		ID:          questID,
+		Name:        name,
+		Description: description,
+		Steps:       steps,
+		RewardMoney: rewardMoney,
+	}
+
+	for _, step := range steps {
+		if step.Type != model.QuestStepObtain {
+			continue
+		}
+		definition, ok := s.itemDefinitions[step.ItemID]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		step.Item = definition
+	}
+
+	for id, quantity := range rewardItems {
+		definition, ok := s.itemDefinitions[id]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		quest.RewardItems = append(quest.RewardItems, model.QuestRewardItem{Definition: definition, Quantity: quantity})
+	}
+
+	s.quests[questID] = quest
+	return quest, nil
+}
+
+// DestroyQuest removes a quest. Characters keep their progress in case it comes back.
+func (s *Simulation) DestroyQuest(questID model.QuestID) {
+	delete(s.quests, questID)
+}
+
+// CreateSocial creates a social, replacing any social that already has the name.
+func (s *Simulation) CreateSocial(name string, untargeted, targeted, self model.SocialMessages) *model.Social {
+	social := model.NewSocial(strings.ToLower(name), untargeted, targeted, self)
+	s.socials[social.Name] = social
+	return social
+}
+
+// DestroySocial removes a social.
+func (s *Simulation) DestroySocial(name string) {
+	delete(s.socials, strings.ToLower(name))
+}
+
+// CreateBackground creates a character background, replacing any background that already has the ID.
+func (s *Simulation) CreateBackground(backgroundID model.BackgroundID, name, description string, money int64, kit map[model.ItemDefinitionID]int) (*model.Background, error) {
+	background := &model.Background{
+		ID:          backgroundID,
+		Name:        name,
+		Description: description,
+		Money:       money,
+	}
+
+	for id, quantity := range kit {
+		definition, ok := s.itemDefinitions[id]
+		if !ok {
+			return nil, ErrItemDefinitionNotFound
+		}
+		background.Kit = append(background.Kit, model.BackgroundItem{Definition: definition, Quantity: quantity})
+	}
+
+	s.backgrounds[backgroundID] = background
+	return background, nil
+}
+
+// DestroyBackground removes a character background. Characters that already chose it keep what they were given.
+func (s *Simulation) DestroyBackground(backgroundID model.BackgroundID) {
+	delete(s.backgrounds, backgroundID)
+}